@@ -2,15 +2,22 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"todo-cli/internal/quickadd"
 	"todo-cli/internal/todo"
 )
 
 var (
-	addPriority string
-	addDueDate  string
+	addPriority   string
+	addDueDate    string
+	addTags       []string
+	addProject    string
+	addParent     int
+	addRepeat     string
+	addRepeatFrom string
 )
 
 // addCmd represents the add command
@@ -19,70 +26,136 @@ var addCmd = &cobra.Command{
 	Short: "Add a new task",
 	Long: `Add a new task to your todo list.
 
-You can specify priority and due date for better organization.
+You can specify priority and due date for better organization, either with
+flags or inline in the title:
+
+  !high, !medium, !low     priority (also !h, !m, !l, or !!! and !! for high
+                           and medium)
+  +tag                     add a tag (repeatable)
+  @project                 set the project, e.g. @work.backend
+  due:fri                  set the due date; quote dates with spaces:
+                           due:"next monday 9am"
+  rec:weekly               repeat the task; rec:"every 2nd tuesday"
+
+Start a word with a backslash to keep it in the title as written, e.g. \+1.
+Flags win over inline fields.
 
 Examples:
   todo add "Buy groceries"
   todo add "Finish project" --priority=high
   todo add "Meeting with team" --due=2025-10-05
-  todo add "Complete assignment" --priority=medium --due=2025-10-10`,
+  todo add "Send report" --due="fri 5pm"
+  todo add "Renew passport" --due="in 3 weeks"
+  todo add "Complete assignment" --priority=medium --due=2025-10-10
+  todo add "Call the plumber +home +urgent"
+  todo add "Pay rent !high @home due:eom rec:monthly"
+  todo add "Vote \+1 on the proposal" +work
+  todo add "Review pull request" --tag=work
+  todo add "Fix login bug" --project=work.backend.auth
+  todo add "Write tests" --parent=4    # Add as a subtask of task 4
+  todo add "Team sync" --due=2025-10-14 --repeat="every 2nd tuesday"
+  todo add "Water plants" --repeat="every 3 days" --repeat-from=completion`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Join all arguments to form the task title
-		title := ""
-		for i, arg := range args {
-			if i > 0 {
-				title += " "
-			}
-			title += arg
+		// Take inline fields such as !high, +tag, @project and due:fri out of
+		// the title
+		parsed, err := quickadd.New(dateParser()).ParseArgs(args)
+		if err != nil {
+			return todo.InvalidInput(err)
 		}
+		opts := parsed.AddOptions()
+		opts.Tags = append(opts.Tags, addTags...)
+		opts.ParentID = addParent
 
-		// Parse priority
-		var priority todo.Priority
+		// Flags win over inline fields; the priority falls back to the
+		// configured default
 		if addPriority != "" {
 			if !todo.ValidatePriority(addPriority) {
-				return fmt.Errorf("invalid priority '%s'. Valid options: low, medium, high", addPriority)
+				return todo.InvalidInput(fmt.Errorf("invalid priority '%s'. Valid options: low, medium, high", addPriority))
 			}
-			priority = todo.Priority(addPriority)
+			opts.Priority = todo.Priority(addPriority)
+		} else if opts.Priority == "" {
+			opts.Priority = todo.Priority(appConfig.DefaultPriority)
 		}
 
-		// Parse due date
-		var dueDate *time.Time
 		if addDueDate != "" {
-			parsedDate, err := time.Parse("2006-01-02", addDueDate)
+			parsedDate, allDay, err := parseDueDate(addDueDate)
 			if err != nil {
-				// Try parsing with time
-				parsedDate, err = time.Parse("2006-01-02 15:04", addDueDate)
-				if err != nil {
-					return fmt.Errorf("invalid due date format. Use YYYY-MM-DD or YYYY-MM-DD HH:MM")
-				}
+				return err
 			}
-			dueDate = &parsedDate
+			opts.DueDate, opts.DueAllDay = &parsedDate, allDay
+		}
+
+		if addProject != "" {
+			opts.Project = addProject
+		}
+
+		if addRepeat != "" {
+			opts.Recurrence = addRepeat
+		}
+
+		if cmd.Flags().Changed("repeat-from") {
+			opts.RecurFrom = todo.RecurFrom(addRepeatFrom)
 		}
 
 		// Add the task
-		task, err := manager.AddTask(title, priority, dueDate)
+		task, err := manager.AddTaskWithOptions(parsed.Title, opts)
 		if err != nil {
 			return fmt.Errorf("failed to add task: %w", err)
 		}
 
+		if structuredOutput() {
+			return writeOutput(newTaskResult(task))
+		}
+
 		// Display success message
 		fmt.Printf("✅ Task added successfully!\n")
 		fmt.Printf("   ID: %d\n", task.ID)
 		fmt.Printf("   Title: %s\n", task.Title)
+		if len(parsed.Tokens) > 0 {
+			fmt.Printf("   Parsed from title: %s\n", strings.Join(parsed.Tokens, " "))
+		}
 		fmt.Printf("   Priority: %s\n", task.Priority)
 		if task.DueDate != nil {
-			fmt.Printf("   Due: %s\n", task.DueDate.Format("2006-01-02 15:04"))
+			fmt.Printf("   Due: %s\n", formatDue(task))
+		}
+		if task.ParentID != 0 {
+			fmt.Printf("   Subtask of: %d\n", task.ParentID)
+		}
+		if task.Project != "" {
+			fmt.Printf("   Project: %s\n", task.Project)
+		}
+		if len(task.Tags) > 0 {
+			fmt.Printf("   Tags: %s\n", task.FormatTags())
+		}
+		if task.IsRecurring() {
+			fmt.Printf("   Repeats: %s\n", task.DescribeRecurrence())
 		}
 
 		return nil
 	},
 }
 
+// parseDueDate parses a due date given on the command line, such as
+// "2025-10-20", "tomorrow 5pm" or "in 3 days", and reports whether it is an
+// all-day due date (no time of day given)
+func parseDueDate(value string) (time.Time, bool, error) {
+	parsedDate, hasTime, err := dateParser().Parse(value)
+	if err != nil {
+		return time.Time{}, false, todo.InvalidInput(fmt.Errorf("invalid due date: %w", err))
+	}
+	return parsedDate, !hasTime, nil
+}
+
 func init() {
 	rootCmd.AddCommand(addCmd)
 
 	// Add flags
 	addCmd.Flags().StringVarP(&addPriority, "priority", "p", "", "Task priority (low, medium, high)")
-	addCmd.Flags().StringVarP(&addDueDate, "due", "d", "", "Due date (YYYY-MM-DD or YYYY-MM-DD HH:MM)")
-}
\ No newline at end of file
+	addCmd.Flags().StringVarP(&addDueDate, "due", "d", "", "Due date (YYYY-MM-DD, YYYY-MM-DD HH:MM, or e.g. tomorrow, fri 5pm, in 3 days, eom)")
+	addCmd.Flags().StringVarP(&addProject, "project", "P", "", "Project, with levels separated by dots (e.g. work.backend)")
+	addCmd.Flags().IntVar(&addParent, "parent", 0, "ID of the task to add this one under as a subtask")
+	addCmd.Flags().StringVar(&addRepeat, "repeat", "", "Repeat the task, e.g. \"weekly\", \"every 2nd tuesday\", \"every 3 days until 2025-12-31\"")
+	addCmd.Flags().StringVar(&addRepeatFrom, "repeat-from", "due", "Count the next occurrence from the due date or the completion date (due, completion)")
+	addCmd.Flags().StringSliceVarP(&addTags, "tag", "t", nil, "Tag to add (repeatable or comma-separated)")
+}
@@ -2,33 +2,181 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	restoreForce bool
+)
+
 // backupCmd represents the backup command
 var backupCmd = &cobra.Command{
 	Use:   "backup",
 	Short: "Create a backup of your tasks",
-	Long: `Create a backup of your tasks file.
+	Long: `Create a timestamped backup of your tasks.
 
-This creates a backup file with the .backup extension in the same directory
-as your tasks file.
+Backups are kept in a "backups" directory next to your tasks file. Only the
+most recent backups are kept (10 by default, see --backup-retention).
 
-Example:
-  todo backup`,
+Examples:
+  todo backup                     # Create a backup
+  todo backup list                # List available backups
+  todo backup restore 2           # Restore the second newest backup
+  todo backup restore 20251005    # Restore the newest backup from Oct 5, 2025`,
+	Annotations: map[string]string{annotationWithoutTasks: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := manager.BackupTasks(); err != nil {
+		backup, err := manager.BackupTasks()
+		if err != nil {
 			return fmt.Errorf("failed to create backup: %w", err)
 		}
 
+		if structuredOutput() {
+			return writeOutput(backupResult{Backup: newBackupRecord(*backup)})
+		}
+
 		fmt.Printf("💾 Backup created successfully!\n")
-		fmt.Printf("   Location: %s.backup\n", manager.GetStoragePath())
+		if backup.Path != "" {
+			fmt.Printf("   Location: %s\n", backup.Path)
+		}
+		fmt.Printf("   Created: %s\n", backup.Timestamp.In(appLocation).Format(backupTimeLayout))
+
+		return nil
+	},
+}
+
+// backupListCmd represents the backup list command
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available backups",
+	Long: `List available backups, newest first.
+
+The ID or timestamp shown can be passed to 'todo backup restore'.
+
+Example:
+  todo backup list`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationWithoutTasks: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backups, err := manager.ListBackups()
+		if err != nil {
+			return fmt.Errorf("failed to list backups: %w", err)
+		}
+
+		if structuredOutput() {
+			return writeOutput(newBackupsResult(backups))
+		}
+
+		if len(backups) == 0 {
+			fmt.Println("💾 No backups found. Create one with: todo backup")
+			return nil
+		}
+
+		fmt.Printf("\n💾 Backups (%d)\n", len(backups))
+		fmt.Println(strings.Repeat("─", 60))
+
+		for _, backup := range backups {
+			fmt.Printf("[%d] %-20s %8s  %s\n",
+				backup.ID,
+				backup.Timestamp.In(appLocation).Format(backupTimeLayout),
+				formatSize(backup.Size),
+				backup.Path,
+			)
+		}
+
+		fmt.Println()
+		return nil
+	},
+}
+
+// backupRestoreCmd represents the backup restore command
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore [id|timestamp]",
+	Short: "Restore tasks from a backup",
+	Long: `Replace your tasks with a backup.
+
+The backup is identified by its ID from 'todo backup list' or by a timestamp
+(YYYYMMDD-HHMMSS in UTC as in the file name, or "YYYY-MM-DD HH:MM" as shown by
+'todo backup list', or any prefix of them). The backup is
+validated before anything is changed, and your current tasks are backed up
+first so a restore can itself be undone.
+
+Examples:
+  todo backup restore 1                  # Restore the newest backup
+  todo backup restore 20251005-1430      # Restore by timestamp
+  todo backup restore 3 --force          # Restore without confirmation`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationWithoutTasks: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Confirm restore unless --force is used or confirmations are off
+		if err := requireForce(restoreForce); err != nil {
+			return err
+		}
+		if !restoreForce && appConfig.Confirm {
+			fmt.Printf("⚠️  This will replace all current tasks with backup '%s'.\n", args[0])
+		}
+
+		if !confirmed(restoreForce, "\nType 'yes' to confirm restore: ") {
+			fmt.Println("❌ Restore cancelled.")
+			return nil
+		}
+
+		backup, err := manager.RestoreBackup(backupRef(args[0]))
+		if err != nil {
+			return fmt.Errorf("failed to restore backup: %w", err)
+		}
+
+		if structuredOutput() {
+			return writeOutput(backupResult{Backup: newBackupRecord(*backup)})
+		}
+
+		fmt.Printf("♻️  Backup restored successfully!\n")
+		fmt.Printf("   Backup: %s\n", backup.Timestamp.In(appLocation).Format(backupTimeLayout))
+		if backup.Path != "" {
+			fmt.Printf("   Location: %s\n", backup.Path)
+		}
 
 		return nil
 	},
 }
 
+// backupTimeLayout is how backup times are shown, in the configured time zone
+const backupTimeLayout = "2006-01-02 15:04:05"
+
+// backupRef turns a restore reference written as a time shown by backup
+// list, such as "2025-10-05 14:30", into the UTC form of backup file names,
+// since storage would read it in the system time zone. Other references are
+// returned unchanged.
+func backupRef(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if t, err := time.ParseInLocation(backupTimeLayout, ref, appLocation); err == nil {
+		return t.UTC().Format("20060102-150405")
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", ref, appLocation); err == nil {
+		return t.UTC().Format("20060102-1504")
+	}
+	return ref
+}
+
+// formatSize formats a byte count for display
+func formatSize(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(backupCmd)
-}
\ No newline at end of file
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+
+	// Add flags
+	backupRestoreCmd.Flags().BoolVarP(&restoreForce, "force", "f", false, "Restore without confirmation")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	blockOn   int
+	unblockOn int
+)
+
+// blockCmd represents the block command
+var blockCmd = &cobra.Command{
+	Use:   "block [task_id] --on [task_id]",
+	Short: "Make a task wait for another task",
+	Long: `Mark a task as blocked until another task is completed.
+
+A task can depend on several tasks. Dependencies that would make a task wait
+on itself, directly or through other tasks, are refused.
+
+Examples:
+  todo block 5 --on 3      # Task 5 can't start until task 3 is done
+  todo list --blocked      # Show tasks waiting on other tasks`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Parse task ID
+		taskID, err := parseTaskID(args[0])
+		if err != nil {
+			return err
+		}
+
+		task, err := manager.BlockTask(taskID, blockOn)
+		if err != nil {
+			return fmt.Errorf("failed to block task: %w", err)
+		}
+
+		if structuredOutput() {
+			return writeOutput(newTaskResult(task))
+		}
+
+		// Display success message
+		fmt.Printf("🔒 Task blocked successfully!\n")
+		fmt.Printf("   ID: %d\n", task.ID)
+		fmt.Printf("   Title: %s\n", task.Title)
+		fmt.Printf("   Depends on: %s\n", formatIDs(task.DependsOn, ", "))
+
+		return nil
+	},
+}
+
+// unblockCmd represents the unblock command
+var unblockCmd = &cobra.Command{
+	Use:   "unblock [task_id]",
+	Short: "Remove a task's dependencies",
+	Long: `Stop a task waiting for another task, or for all tasks if --on is not given.
+
+Examples:
+  todo unblock 5 --on 3    # Task 5 no longer waits for task 3
+  todo unblock 5           # Task 5 no longer waits for any task`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Parse task ID
+		taskID, err := parseTaskID(args[0])
+		if err != nil {
+			return err
+		}
+
+		task, err := manager.UnblockTask(taskID, unblockOn)
+		if err != nil {
+			return fmt.Errorf("failed to unblock task: %w", err)
+		}
+
+		if structuredOutput() {
+			return writeOutput(newTaskResult(task))
+		}
+
+		// Display success message
+		fmt.Printf("🔓 Task unblocked successfully!\n")
+		fmt.Printf("   ID: %d\n", task.ID)
+		fmt.Printf("   Title: %s\n", task.Title)
+		if len(task.DependsOn) > 0 {
+			fmt.Printf("   Still depends on: %s\n", formatIDs(task.DependsOn, ", "))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(blockCmd)
+	rootCmd.AddCommand(unblockCmd)
+
+	// Add flags
+	blockCmd.Flags().IntVar(&blockOn, "on", 0, "ID of the task that must be completed first")
+	blockCmd.MarkFlagRequired("on")
+	unblockCmd.Flags().IntVar(&unblockOn, "on", 0, "ID of the task to stop waiting for (all if omitted)")
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/spf13/cobra"
 )
@@ -13,6 +12,10 @@ var completeCmd = &cobra.Command{
 	Short: "Mark a task as completed",
 	Long: `Mark a task as completed by providing its ID.
 
+Completing a task also completes its pending subtasks. Tasks that were
+waiting only on it are listed as unblocked, and completing a recurring task
+creates its next occurrence.
+
 Examples:
   todo complete 1      # Mark task with ID 1 as completed
   todo complete 5      # Mark task with ID 5 as completed
@@ -21,22 +24,39 @@ You can find task IDs by running: todo list`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Parse task ID
-		taskID, err := strconv.Atoi(args[0])
+		taskID, err := parseTaskID(args[0])
 		if err != nil {
-			return fmt.Errorf("invalid task ID '%s'. Please provide a valid number", args[0])
+			return err
 		}
 
 		// Complete the task
-		task, err := manager.CompleteTask(taskID)
+		result, err := manager.CompleteTask(taskID)
 		if err != nil {
 			return fmt.Errorf("failed to complete task: %w", err)
 		}
 
+		if structuredOutput() {
+			return writeOutput(newCompleteResult(result))
+		}
+
+		task := result.Task
+
 		// Display success message
 		fmt.Printf("✅ Task completed successfully!\n")
 		fmt.Printf("   ID: %d\n", task.ID)
 		fmt.Printf("   Title: %s\n", task.Title)
-		fmt.Printf("   Completed at: %s\n", task.UpdatedAt.Format("2006-01-02 15:04:05"))
+		fmt.Printf("   Completed at: %s\n", task.CompletedAt.In(appLocation).Format("2006-01-02 15:04:05"))
+		if len(result.Subtasks) > 0 {
+			fmt.Printf("   Also completed %d subtask(s)\n", len(result.Subtasks))
+		}
+		for _, unblocked := range result.Unblocked {
+			fmt.Printf("🔓 Unblocked: [%d] %s\n", unblocked.ID, unblocked.Title)
+		}
+		if next := result.Next; next != nil {
+			fmt.Printf("🔁 Next occurrence: [%d] due %s\n", next.ID, formatDue(next))
+		} else if task.IsRecurring() {
+			fmt.Printf("🔁 This was the last occurrence\n")
+		}
 
 		return nil
 	},
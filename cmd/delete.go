@@ -2,13 +2,14 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
 
 	"github.com/spf13/cobra"
+	"todo-cli/internal/todo"
 )
 
 var (
-	deleteForce bool
+	deleteForce     bool
+	deleteRecursive bool
 )
 
 // deleteCmd represents the delete command
@@ -20,6 +21,9 @@ var deleteCmd = &cobra.Command{
 Examples:
   todo delete 1        # Delete task with ID 1 (with confirmation)
   todo delete 5 --force # Delete task with ID 5 without confirmation
+  todo delete 3 -r     # Delete task 3 and all of its subtasks
+
+A task with subtasks is only deleted with --recursive.
 
 You can find task IDs by running: todo list
 
@@ -27,9 +31,9 @@ Warning: This action cannot be undone!`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Parse task ID
-		taskID, err := strconv.Atoi(args[0])
+		taskID, err := parseTaskID(args[0])
 		if err != nil {
-			return fmt.Errorf("invalid task ID '%s'. Please provide a valid number", args[0])
+			return err
 		}
 
 		// Get the task first to show what will be deleted
@@ -38,8 +42,16 @@ Warning: This action cannot be undone!`,
 			return fmt.Errorf("failed to find task: %w", err)
 		}
 
-		// Confirm deletion unless --force is used
-		if !deleteForce {
+		subtasks := manager.GetDescendants(taskID)
+		if len(subtasks) > 0 && !deleteRecursive {
+			return fmt.Errorf("task %d has %d subtask(s). Use --recursive to delete them too", taskID, len(subtasks))
+		}
+
+		// Confirm deletion unless --force is used or confirmations are off
+		if err := requireForce(deleteForce); err != nil {
+			return err
+		}
+		if !deleteForce && appConfig.Confirm {
 			fmt.Printf("⚠️  Are you sure you want to delete this task?\n")
 			fmt.Printf("   ID: %d\n", task.ID)
 			fmt.Printf("   Title: %s\n", task.Title)
@@ -49,27 +61,40 @@ Warning: This action cannot be undone!`,
 			} else {
 				fmt.Printf("Pending\n")
 			}
-			fmt.Printf("\nType 'yes' to confirm deletion: ")
-			
-			var confirmation string
-			fmt.Scanln(&confirmation)
-			
-			if confirmation != "yes" && confirmation != "y" && confirmation != "YES" && confirmation != "Y" {
-				fmt.Println("❌ Deletion cancelled.")
-				return nil
+			if len(subtasks) > 0 {
+				fmt.Printf("   Subtasks: %d (will also be deleted)\n", len(subtasks))
 			}
 		}
 
+		if !confirmed(deleteForce, "\nType 'yes' to confirm deletion: ") {
+			fmt.Println("❌ Deletion cancelled.")
+			return nil
+		}
+
 		// Delete the task
-		deletedTask, err := manager.DeleteTask(taskID)
+		var deletedTasks []*todo.Task
+		if deleteRecursive {
+			deletedTasks, err = manager.DeleteTaskTree(taskID)
+		} else {
+			var deletedTask *todo.Task
+			deletedTask, err = manager.DeleteTask(taskID)
+			deletedTasks = []*todo.Task{deletedTask}
+		}
 		if err != nil {
 			return fmt.Errorf("failed to delete task: %w", err)
 		}
 
+		if structuredOutput() {
+			return writeOutput(newTaskListResult(deletedTasks))
+		}
+
 		// Display success message
 		fmt.Printf("🗑️  Task deleted successfully!\n")
-		fmt.Printf("   ID: %d\n", deletedTask.ID)
-		fmt.Printf("   Title: %s\n", deletedTask.Title)
+		fmt.Printf("   ID: %d\n", deletedTasks[0].ID)
+		fmt.Printf("   Title: %s\n", deletedTasks[0].Title)
+		if len(deletedTasks) > 1 {
+			fmt.Printf("   Subtasks deleted: %d\n", len(deletedTasks)-1)
+		}
 
 		return nil
 	},
@@ -80,4 +105,5 @@ func init() {
 	
 	// Add flags
 	deleteCmd.Flags().BoolVarP(&deleteForce, "force", "f", false, "Delete without confirmation")
+	deleteCmd.Flags().BoolVarP(&deleteRecursive, "recursive", "r", false, "Also delete the task's subtasks")
 }
\ No newline at end of file
@@ -0,0 +1,334 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"todo-cli/internal/todo"
+)
+
+var (
+	editTitle      string
+	editPriority   string
+	editDueDate    string
+	editClearDue   bool
+	editTags       []string
+	editProject    string
+	editParent     int
+	editRepeat     string
+	editRepeatFrom string
+	editInEditor   bool
+)
+
+// editableTask is the document opened in $EDITOR by 'todo edit'
+type editableTask struct {
+	Title      string   `yaml:"title"`
+	Priority   string   `yaml:"priority"`
+	Due        string   `yaml:"due"`
+	Tags       []string `yaml:"tags"`
+	Project    string   `yaml:"project"`
+	Repeat     string   `yaml:"repeat"`
+	RepeatFrom string   `yaml:"repeat_from"`
+}
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit [task_id]",
+	Short: "Edit an existing task",
+	Long: `Change the title, priority, due date, tags or project of an existing task.
+The task keeps its ID and creation date.
+
+Without any field flags (or with --editor) the task is opened as YAML in
+$EDITOR, and whatever you change is applied when the editor exits.
+
+Examples:
+  todo edit 3 --title="Finish report draft"
+  todo edit 3 --priority=high --due=2025-10-10
+  todo edit 3 --clear-due
+  todo edit 3 --tags=work,urgent   # Replace the tags (--tags= removes them)
+  todo edit 3 --project=work.api   # Move to a project (--project= removes it)
+  todo edit 3 --parent=1           # Make it a subtask of task 1 (0 for top level)
+  todo edit 3 --repeat=weekly      # Repeat the task (--repeat= stops it repeating)
+  todo edit 3 --repeat-from=completion
+  todo edit 3                  # Edit in $EDITOR`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Parse task ID
+		taskID, err := parseTaskID(args[0])
+		if err != nil {
+			return err
+		}
+
+		task, err := manager.GetTask(taskID)
+		if err != nil {
+			return fmt.Errorf("failed to find task: %w", err)
+		}
+
+		var update todo.TaskUpdate
+		flags := cmd.Flags()
+		if editInEditor || !(flags.Changed("title") || flags.Changed("priority") || flags.Changed("due") || flags.Changed("tags") || flags.Changed("project") || flags.Changed("parent") ||
+			flags.Changed("repeat") || flags.Changed("repeat-from") || editClearDue) {
+			// Scripts cannot answer an editor, so they must say what to change
+			if structuredOutput() {
+				return todo.InvalidInput(fmt.Errorf("editing in $EDITOR is not available with --output=%s; give the fields to change as flags", outputFormat))
+			}
+			update, err = editTaskInEditor(task)
+		} else {
+			update, err = editUpdateFromFlags(cmd)
+		}
+		if err != nil {
+			return err
+		}
+
+		if update.IsEmpty() {
+			if structuredOutput() {
+				return writeOutput(newTaskResult(task))
+			}
+			fmt.Println("ℹ️  No changes made.")
+			return nil
+		}
+
+		task, err = manager.UpdateTask(taskID, update)
+		if err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+
+		if structuredOutput() {
+			return writeOutput(newTaskResult(task))
+		}
+
+		// Display success message
+		fmt.Printf("✏️  Task updated successfully!\n")
+		fmt.Printf("   ID: %d\n", task.ID)
+		fmt.Printf("   Title: %s\n", task.Title)
+		fmt.Printf("   Priority: %s\n", task.Priority)
+		if task.DueDate != nil {
+			fmt.Printf("   Due: %s\n", formatDue(task))
+		}
+		if task.Project != "" {
+			fmt.Printf("   Project: %s\n", task.Project)
+		}
+		if len(task.Tags) > 0 {
+			fmt.Printf("   Tags: %s\n", task.FormatTags())
+		}
+		if task.IsRecurring() {
+			fmt.Printf("   Repeats: %s\n", task.DescribeRecurrence())
+		}
+
+		return nil
+	},
+}
+
+// editUpdateFromFlags builds an update from the flags that were given
+func editUpdateFromFlags(cmd *cobra.Command) (todo.TaskUpdate, error) {
+	var update todo.TaskUpdate
+	flags := cmd.Flags()
+
+	if flags.Changed("title") {
+		update.Title = &editTitle
+	}
+
+	if flags.Changed("priority") {
+		if !todo.ValidatePriority(editPriority) {
+			return update, todo.InvalidInput(fmt.Errorf("invalid priority '%s'. Valid options: low, medium, high", editPriority))
+		}
+		priority := todo.Priority(editPriority)
+		update.Priority = &priority
+	}
+
+	if flags.Changed("due") {
+		dueDate, allDay, err := parseDueDate(editDueDate)
+		if err != nil {
+			return update, err
+		}
+		update.DueDate, update.DueAllDay = &dueDate, allDay
+	}
+
+	if flags.Changed("tags") {
+		tags := editTags
+		update.Tags = &tags
+	}
+
+	if flags.Changed("project") {
+		update.Project = &editProject
+	}
+
+	if flags.Changed("parent") {
+		update.ParentID = &editParent
+	}
+
+	if flags.Changed("repeat") {
+		update.Recurrence = &editRepeat
+	}
+
+	if flags.Changed("repeat-from") {
+		if !todo.ValidateRecurFrom(editRepeatFrom) {
+			return update, todo.InvalidInput(fmt.Errorf("invalid repeat mode '%s'. Valid options: due, completion", editRepeatFrom))
+		}
+		from := todo.RecurFrom(editRepeatFrom)
+		update.RecurFrom = &from
+	}
+
+	update.ClearDueDate = editClearDue
+	return update, nil
+}
+
+// editTaskInEditor opens the task as YAML in the user's editor and returns
+// an update containing the fields that were changed
+func editTaskInEditor(task *todo.Task) (todo.TaskUpdate, error) {
+	var update todo.TaskUpdate
+
+	original := editableTask{
+		Title:      task.Title,
+		Priority:   string(task.Priority),
+		Tags:       task.Tags,
+		Project:    task.Project,
+		Repeat:     task.Recurrence,
+		RepeatFrom: string(task.RecurFrom),
+	}
+	if task.DueDate != nil {
+		original.Due = formatEditableDue(task)
+	}
+
+	data, err := yaml.Marshal(original)
+	if err != nil {
+		return update, fmt.Errorf("failed to encode task: %w", err)
+	}
+
+	file, err := os.CreateTemp("", fmt.Sprintf("todo-%d-*.yaml", task.ID))
+	if err != nil {
+		return update, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	header := fmt.Sprintf("# Editing task %d. Save and close the editor to apply changes.\n"+
+		"# priority: low, medium or high\n"+
+		"# due: YYYY-MM-DD or YYYY-MM-DD HH:MM, empty for no due date\n"+
+		"# tags: a list such as [work, urgent]\n"+
+		"# project: dot-separated levels such as work.backend, empty for none\n"+
+		"# repeat: a rule such as weekly or \"every 2nd tuesday\", empty for none\n"+
+		"# repeat_from: due or completion\n", task.ID)
+	if _, err := file.WriteString(header + string(data)); err != nil {
+		file.Close()
+		return update, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	file.Close()
+
+	if err := runEditor(file.Name()); err != nil {
+		return update, err
+	}
+
+	data, err = os.ReadFile(file.Name())
+	if err != nil {
+		return update, fmt.Errorf("failed to read edited task: %w", err)
+	}
+
+	var edited editableTask
+	if err := yaml.Unmarshal(data, &edited); err != nil {
+		return update, todo.InvalidInput(fmt.Errorf("failed to parse edited task: %w", err))
+	}
+
+	// Only apply what changed
+	if edited.Title != original.Title {
+		update.Title = &edited.Title
+	}
+
+	if edited.Priority != original.Priority {
+		if !todo.ValidatePriority(edited.Priority) {
+			return update, todo.InvalidInput(fmt.Errorf("invalid priority '%s'. Valid options: low, medium, high", edited.Priority))
+		}
+		priority := todo.Priority(edited.Priority)
+		update.Priority = &priority
+	}
+
+	edited.Due = strings.TrimSpace(edited.Due)
+	if edited.Due != original.Due {
+		if edited.Due == "" {
+			update.ClearDueDate = true
+		} else {
+			dueDate, allDay, err := parseDueDate(edited.Due)
+			if err != nil {
+				return update, err
+			}
+			update.DueDate, update.DueAllDay = &dueDate, allDay
+		}
+	}
+
+	if strings.Join(edited.Tags, " ") != strings.Join(original.Tags, " ") {
+		tags := edited.Tags
+		update.Tags = &tags
+	}
+
+	edited.Project = strings.TrimSpace(edited.Project)
+	if edited.Project != original.Project {
+		update.Project = &edited.Project
+	}
+
+	edited.Repeat = strings.TrimSpace(edited.Repeat)
+	if edited.Repeat != original.Repeat {
+		update.Recurrence = &edited.Repeat
+	}
+
+	if edited.RepeatFrom != original.RepeatFrom {
+		if !todo.ValidateRecurFrom(edited.RepeatFrom) {
+			return update, todo.InvalidInput(fmt.Errorf("invalid repeat mode '%s'. Valid options: due, completion", edited.RepeatFrom))
+		}
+		from := todo.RecurFrom(edited.RepeatFrom)
+		update.RecurFrom = &from
+	}
+
+	return update, nil
+}
+
+// formatEditableDue formats a task's due date the way parseDueDate reads it
+// back, in the configured time zone
+func formatEditableDue(task *todo.Task) string {
+	due := task.DueIn(appLocation)
+	if task.DueAllDay {
+		return due.Format("2006-01-02")
+	}
+	return due.Format("2006-01-02 15:04")
+}
+
+// runEditor opens path in $VISUAL, $EDITOR or vi and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// The editor setting may include arguments, such as "code --wait"
+	parts := strings.Fields(editor)
+	editorCmd := exec.Command(parts[0], append(parts[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("editor '%s' failed: %w", editor, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	// Add flags
+	editCmd.Flags().StringVarP(&editTitle, "title", "t", "", "New task title")
+	editCmd.Flags().StringVarP(&editPriority, "priority", "p", "", "New task priority (low, medium, high)")
+	editCmd.Flags().StringVarP(&editDueDate, "due", "d", "", "New due date (YYYY-MM-DD, YYYY-MM-DD HH:MM, or e.g. tomorrow, fri 5pm, in 3 days, eom)")
+	editCmd.Flags().BoolVar(&editClearDue, "clear-due", false, "Remove the due date")
+	editCmd.Flags().StringVar(&editProject, "project", "", "Move the task to a project")
+	editCmd.Flags().IntVar(&editParent, "parent", 0, "Make the task a subtask of this task (0 for top level)")
+	editCmd.Flags().StringVar(&editRepeat, "repeat", "", "Repeat the task, e.g. \"weekly\" or \"every 2nd tuesday\"")
+	editCmd.Flags().StringVar(&editRepeatFrom, "repeat-from", "", "Count the next occurrence from the due date or the completion date (due, completion)")
+	editCmd.Flags().StringSliceVar(&editTags, "tags", nil, "Replace the task tags (comma-separated)")
+	editCmd.Flags().BoolVarP(&editInEditor, "editor", "e", false, "Edit the task in $EDITOR")
+}
@@ -12,8 +12,11 @@ import (
 )
 
 var (
-	exportFormat string
-	exportFile   string
+	exportFormat  string
+	exportFile    string
+	exportProject string
+	exportQuery   string
+	exportView    string
 )
 
 // exportCmd represents the export command
@@ -27,9 +30,13 @@ Supported formats:
   - txt: Plain text format
 
 Examples:
-  todo export --format=csv --file=tasks.csv
-  todo export --format=txt --file=tasks.txt
-  todo export --format=csv                    # Exports to tasks.csv`,
+  todo export --format=csv --output-file=tasks.csv
+  todo export --format=txt -o tasks.txt
+  todo export --format=csv                    # Exports to tasks.csv
+  todo export --format=csv --project=work     # Only work and its sub-projects
+  todo export --format=csv --query='tag:ops and not done'
+  todo export --format=csv --view=urgent      # Tasks shown by a saved view
+  todo export --format=csv --completed-since=-7d   # Finished in the last week`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Set default filename based on format
 		if exportFile == "" {
@@ -43,11 +50,52 @@ Examples:
 			}
 		}
 
-		// Get all tasks
-		allTasks := manager.ListTasks(todo.FilterOptions{
+		// Never write the export over the task list itself
+		if isTaskFile(exportFile) {
+			return todo.InvalidInput(fmt.Errorf("cannot export to %s: it is the task list", exportFile))
+		}
+
+		if _, err := todo.NormalizeProject(exportProject); err != nil {
+			return err
+		}
+
+		// Get all tasks, or those of the selected view, project or query
+		filter := todo.FilterOptions{
 			ShowCompleted: true,
 			ShowPending:   true,
-		})
+		}
+		if exportView != "" {
+			view, err := findView(exportView)
+			if err != nil {
+				return err
+			}
+			if filter, err = viewFilter(*view); err != nil {
+				return fmt.Errorf("view '%s' is invalid: %w", view.Name, err)
+			}
+		}
+		if exportProject != "" {
+			filter.Project = exportProject
+		}
+		if err := applyDateFilters(&filter, dateView()); err != nil {
+			return err
+		}
+		if exportQuery != "" {
+			q, err := parseQuery(exportQuery)
+			if err != nil {
+				return err
+			}
+			// Narrow the view's own query rather than replacing it
+			match := queryFilter(q)
+			if where := filter.Where; where != nil {
+				filter.Where = func(task *todo.Task) bool { return where(task) && match(task) }
+			} else {
+				filter.Where = match
+			}
+		}
+		allTasks, err := manager.ListTasks(filter)
+		if err != nil {
+			return err
+		}
 
 		switch exportFormat {
 		case "csv":
@@ -55,7 +103,7 @@ Examples:
 		case "txt":
 			return exportTXT(allTasks, exportFile)
 		default:
-			return fmt.Errorf("unsupported format '%s'. Supported formats: csv, txt", exportFormat)
+			return todo.InvalidInput(fmt.Errorf("unsupported format '%s'. Supported formats: csv, txt", exportFormat))
 		}
 	},
 }
@@ -72,7 +120,7 @@ func exportCSV(tasks []*todo.Task, filename string) error {
 	defer writer.Flush()
 
 	// Write header
-	header := []string{"ID", "Title", "Completed", "Priority", "Due Date", "Created At", "Updated At"}
+	header := []string{"ID", "Title", "Completed", "Priority", "Due Date", "Created At", "Updated At", "Completed At", "Tags", "Project", "Parent ID", "Depends On", "Recurrence", "Recur From"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write header: %w", err)
 	}
@@ -85,12 +133,27 @@ func exportCSV(tasks []*todo.Task, filename string) error {
 			strconv.FormatBool(task.Completed),
 			string(task.Priority),
 			"",
-			task.CreatedAt.Format("2006-01-02 15:04:05"),
-			task.UpdatedAt.Format("2006-01-02 15:04:05"),
+			task.CreatedAt.In(appLocation).Format("2006-01-02 15:04:05"),
+			task.UpdatedAt.In(appLocation).Format("2006-01-02 15:04:05"),
+			"",
+			strings.Join(task.Tags, " "),
+			task.Project,
+			"",
+			formatIDs(task.DependsOn, " "),
+			task.Recurrence,
+			string(task.RecurFrom),
 		}
 
 		if task.DueDate != nil {
-			record[4] = task.DueDate.Format("2006-01-02 15:04:05")
+			record[4] = exportDue(task, "2006-01-02 15:04:05")
+		}
+
+		if task.CompletedAt != nil {
+			record[7] = task.CompletedAt.In(appLocation).Format("2006-01-02 15:04:05")
+		}
+
+		if task.ParentID != 0 {
+			record[10] = strconv.Itoa(task.ParentID)
 		}
 
 		if err := writer.Write(record); err != nil {
@@ -98,7 +161,16 @@ func exportCSV(tasks []*todo.Task, filename string) error {
 		}
 	}
 
-	fmt.Printf("📄 Tasks exported to %s (%d tasks)\n", filename, len(tasks))
+	return reportExport(filename, len(tasks))
+}
+
+// reportExport tells where tasks were exported
+func reportExport(filename string, count int) error {
+	if structuredOutput() {
+		return writeOutput(fileResult{Path: filename, Format: exportFormat, Tasks: count})
+	}
+
+	fmt.Printf("📄 Tasks exported to %s (%d tasks)\n", filename, count)
 	return nil
 }
 
@@ -125,20 +197,68 @@ func exportTXT(tasks []*todo.Task, filename string) error {
 		fmt.Fprintf(file, "    Priority: %s\n", strings.ToUpper(string(task.Priority)))
 		
 		if task.DueDate != nil {
-			fmt.Fprintf(file, "    Due: %s\n", task.DueDate.Format("2006-01-02 15:04"))
+			fmt.Fprintf(file, "    Due: %s\n", exportDue(task, "2006-01-02 15:04"))
 		}
 		
-		fmt.Fprintf(file, "    Created: %s\n", task.CreatedAt.Format("2006-01-02 15:04"))
+		if task.ParentID != 0 {
+			fmt.Fprintf(file, "    Subtask of: %d\n", task.ParentID)
+		}
+
+		if task.Project != "" {
+			fmt.Fprintf(file, "    Project: %s\n", task.Project)
+		}
+
+		if len(task.DependsOn) > 0 {
+			fmt.Fprintf(file, "    Depends on: %s\n", formatIDs(task.DependsOn, ", "))
+		}
+
+		if task.IsRecurring() {
+			fmt.Fprintf(file, "    Repeats: %s\n", task.DescribeRecurrence())
+		}
+
+		if len(task.Tags) > 0 {
+			fmt.Fprintf(file, "    Tags: %s\n", task.FormatTags())
+		}
+
+		fmt.Fprintf(file, "    Created: %s\n", task.CreatedAt.In(appLocation).Format("2006-01-02 15:04"))
 		
-		if task.Completed {
-			fmt.Fprintf(file, "    Completed: %s\n", task.UpdatedAt.Format("2006-01-02 15:04"))
+		if task.Completed && task.CompletedAt != nil {
+			fmt.Fprintf(file, "    Completed: %s\n", task.CompletedAt.In(appLocation).Format("2006-01-02 15:04"))
 		}
 		
 		fmt.Fprintf(file, "\n")
 	}
 
-	fmt.Printf("📄 Tasks exported to %s (%d tasks)\n", filename, len(tasks))
-	return nil
+	return reportExport(filename, len(tasks))
+}
+
+// isTaskFile reports whether path is the task list in use
+func isTaskFile(path string) bool {
+	exported, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	list, err := os.Stat(taskFilePath)
+	return err == nil && os.SameFile(exported, list)
+}
+
+// formatIDs joins task IDs with sep
+func formatIDs(ids []int, sep string) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, sep)
+}
+
+// exportDue formats a task's due date in the configured time zone: just the
+// day for an all-day due date, layout for a timed one
+func exportDue(task *todo.Task, layout string) string {
+	due := task.DueIn(appLocation)
+	if task.DueAllDay {
+		return due.Format("2006-01-02")
+	}
+	return due.Format(layout)
 }
 
 func init() {
@@ -146,5 +266,10 @@ func init() {
 
 	// Add flags
 	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "txt", "Export format (csv, txt)")
-	exportCmd.Flags().StringVarP(&exportFile, "file", "o", "", "Output filename")
-}
\ No newline at end of file
+	exportCmd.Flags().StringVarP(&exportFile, "output-file", "o", "", "File to write the export to")
+	exportCmd.Flags().StringVarP(&exportProject, "project", "P", "", "Export only this project and its sub-projects")
+	exportCmd.Flags().StringVarP(&exportQuery, "query", "q", "", "Export only tasks matching a query (see todo list --help)")
+	exportCmd.Flags().StringVar(&exportView, "view", "", "Export only tasks shown by this saved view")
+	addDateFlags(exportCmd)
+}
+
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCommand runs the todo command line args with $HOME and the working
+// directory in dir, so no real task list or config is touched
+func runCommand(t *testing.T, dir string, args ...string) error {
+	t.Helper()
+	t.Setenv("HOME", dir)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	rootCmd.SetArgs(args)
+	return rootCmd.Execute()
+}
+
+func TestExportLeavesTaskListAlone(t *testing.T) {
+	dir := t.TempDir()
+	list := filepath.Join(dir, "copy.json")
+	if err := runCommand(t, dir, "--file", list, "add", "Keep me"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	before, err := os.ReadFile(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+		output  string // file the export is written to, if any
+	}{
+		{"global --file picks the list", []string{"--file", list, "export", "--format", "csv"}, false, "tasks.csv"},
+		{"--output-file", []string{"--file", list, "export", "--format", "csv", "--output-file", "out.csv"}, false, "out.csv"},
+		{"-o onto the task list", []string{"--file", list, "export", "--format", "csv", "-o", list}, true, ""},
+		{"relative path to the task list", []string{"--file", list, "export", "--format", "txt", "-o", "./copy.json"}, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exportFile = ""
+			err := runCommand(t, dir, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("export error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			after, err := os.ReadFile(list)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(after, before) {
+				t.Errorf("task list changed by export:\n%s", after)
+			}
+
+			if tt.output != "" {
+				data, err := os.ReadFile(filepath.Join(dir, tt.output))
+				if err != nil {
+					t.Fatalf("export not written: %v", err)
+				}
+				if !strings.Contains(string(data), "Keep me") {
+					t.Errorf("export %s does not contain the task:\n%s", tt.output, data)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"todo-cli/internal/todo"
+	"todo-cli/storage"
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a project task list in the current directory",
+	Long: `Create a project-local task list in .todo/ in the current directory.
+
+Commands run in this directory or any directory below it use the project
+list instead of the global one, so work items can live next to the code.
+Use --global to reach the global list from inside a project.
+
+Examples:
+  todo init                   # Create .todo/tasks.json here
+  todo --backend=sqlite init  # Create .todo/tasks.db here
+  todo list --global          # List the global tasks from inside a project`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationWithoutTasks: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+
+		path := storage.ProjectFilePath(cwd, backendFileName(storageBackend))
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("a project task list already exists at %s", path)
+		}
+
+		backend, err := newBackend(storageBackend, path)
+		if err != nil {
+			return err
+		}
+
+		// Saving an empty list creates the file
+		if err := todo.NewManagerWithBackend(backend).SaveTasks(); err != nil {
+			return fmt.Errorf("failed to create task list: %w", err)
+		}
+
+		if structuredOutput() {
+			return writeOutput(fileResult{Path: path, Format: storageBackend})
+		}
+
+		fmt.Printf("📁 Project task list created!\n")
+		fmt.Printf("   Location: %s\n", path)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"todo-cli/internal/query"
+	"todo-cli/internal/search"
 	"todo-cli/internal/todo"
+	"todo-cli/storage"
 )
 
 var (
@@ -14,10 +20,36 @@ var (
 	listPending   bool
 	listPriority  string
 	listSearch    string
+	listProject   string
+	listTags      []string
+	listNoTags    []string
 	listSort      string
 	listStats     bool
+	listFlat      bool
+	listBlocked   bool
+	listUnblocked bool
+	listQuery     string
+	listGroupBy   string
+	listRegex     bool
+
+	// Date filters, as typed; see applyDateFilters
+	listDueBefore       string
+	listDueAfter        string
+	listDueWithin       string
+	listOverdue         bool
+	listNoDue           bool
+	listCreatedBefore   string
+	listCreatedAfter    string
+	listCreatedSince    string
+	listCompletedBefore string
+	listCompletedAfter  string
+	listCompletedSince  string
 )
 
+// searchHighlight marks the parts of titles matching the search being
+// listed, if any
+var searchHighlight *search.Query
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
@@ -30,48 +62,291 @@ Examples:
   todo list --pending                 # List only pending tasks
   todo list --priority=high           # List only high priority tasks
   todo list --search="project"        # Search for tasks containing "project"
+  todo list -s 'deploy "release notes"'  # All words and phrases, best first
+  todo list -s '^fix.*(api|db)' --regex  # Regular expression search
+  todo list --project=work            # List tasks in work and its sub-projects
+  todo list --sort=project            # Group tasks by project
+  todo list --tag=work                # List tasks tagged +work
+  todo list --tag=work --exclude-tag=later
   todo list --sort=priority           # Sort by priority
   todo list --sort=due                # Sort by due date
-  todo list --stats                   # Show task statistics`,
+  todo list --sort=-priority,due,id   # Highest priority first, then by due date
+  todo list --group-by=due-bucket     # Sections: overdue, today, this week, ...
+  todo list --stats                   # Show task statistics
+  todo list --flat                    # Don't nest subtasks under their parents
+  todo list --blocked                 # Tasks waiting on other tasks
+  todo list --due-within=7d           # Due from today through the next 7 days
+  todo list --completed-since=yesterday --stats
+  todo list --overdue                 # Also --no-due, --due-before/after,
+                                      # --created-before/after/since and
+                                      # --completed-before/after/since
+  todo list -q 'priority:high and (tag:ops or due<7d) and not done'
+
+Queries combine conditions with and, or, not and parentheses:
+  priority:high, priority>=medium     tag:ops, tag!=later
+  project:work                        title:deploy, or just deploy
+  due<7d, due:today, due:none         created>-7d, completed>=mon
+  id>10, parent:4                     done, pending, overdue, blocked,
+                                      recurring, subtask (or is:blocked)`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Show statistics if requested
+		view, err := flagView(cmd)
+		if err != nil {
+			return err
+		}
+
+		filter, err := viewFilter(view)
+		if err != nil {
+			return err
+		}
+
+		tasks, err := manager.ListTasks(filter)
+		if err != nil {
+			return err
+		}
+
+		// Show statistics of the selected tasks if requested
 		if listStats {
-			return showStats()
+			return showStats(tasks, viewFlags(view))
+		}
+
+		return showTaskList(tasks, filter, view.GroupBy)
+	},
+}
+
+// flagView collects the filter flags given to cmd into an unnamed view
+func flagView(cmd *cobra.Command) (storage.View, error) {
+	if listBlocked && listUnblocked {
+		return storage.View{}, todo.InvalidInput(fmt.Errorf("--blocked and --unblocked cannot be used together"))
+	}
+
+	view := dateView()
+	view.Completed = listCompleted
+	view.Pending = listPending
+	view.Priority = storage.Priority(listPriority)
+	view.Search = listSearch
+	view.SearchRegex = listRegex
+	view.Project = listProject
+	view.Tags = listTags
+	view.ExcludeTags = listNoTags
+	view.Query = listQuery
+	view.GroupBy = listGroupBy
+
+	// Leave the sort unset unless --sort was given, so the configured one applies
+	if cmd.Flags().Changed("sort") {
+		view.Sort = listSort
+	}
+
+	if listBlocked || listUnblocked {
+		blocked := listBlocked
+		view.Blocked = &blocked
+	}
+	return view, nil
+}
+
+// dateView collects the date filter flags into an unnamed view
+func dateView() storage.View {
+	return storage.View{
+		DueBefore:       listDueBefore,
+		DueAfter:        listDueAfter,
+		DueWithin:       listDueWithin,
+		Overdue:         listOverdue,
+		NoDue:           listNoDue,
+		CreatedBefore:   listCreatedBefore,
+		CreatedAfter:    listCreatedAfter,
+		CreatedSince:    listCreatedSince,
+		CompletedBefore: listCompletedBefore,
+		CompletedAfter:  listCompletedAfter,
+		CompletedSince:  listCompletedSince,
+	}
+}
+
+// applyDateFilters resolves the date filters of a view against the current
+// time and sets them in filter, replacing those already set for the same
+// dates. A day without a time is a whole day: --due-before fri means before
+// Friday starts, --due-after fri after it ends, --completed-since yesterday
+// from the start of yesterday, and --due-within 7d from the start of today
+// until the end of the seventh day.
+func applyDateFilters(filter *todo.FilterOptions, view storage.View) error {
+	if view.NoDue && (view.Overdue || view.DueBefore != "" || view.DueAfter != "" || view.DueWithin != "") {
+		return todo.InvalidInput(fmt.Errorf("--no-due cannot be used with other due date filters"))
+	}
+	if view.DueWithin != "" && (view.DueBefore != "" || view.DueAfter != "") {
+		return todo.InvalidInput(fmt.Errorf("--due-within cannot be used with --due-before or --due-after"))
+	}
+
+	bounds := []struct {
+		flag    string
+		text    string
+		nextDay bool // a day without a time counts from its end
+		set     **time.Time
+	}{
+		{"due-before", view.DueBefore, false, &filter.Due.To},
+		{"due-after", view.DueAfter, true, &filter.Due.From},
+		{"created-before", view.CreatedBefore, false, &filter.Created.To},
+		{"created-after", view.CreatedAfter, true, &filter.Created.From},
+		{"created-since", view.CreatedSince, false, &filter.Created.From},
+		{"completed-before", view.CompletedBefore, false, &filter.Completed.To},
+		{"completed-after", view.CompletedAfter, true, &filter.Completed.From},
+		{"completed-since", view.CompletedSince, false, &filter.Completed.From},
+	}
+
+	setBy := make(map[**time.Time]string)
+	for _, bound := range bounds {
+		if bound.text == "" {
+			continue
+		}
+		if other, ok := setBy[bound.set]; ok {
+			return todo.InvalidInput(fmt.Errorf("--%s and --%s cannot be used together", other, bound.flag))
+		}
+		setBy[bound.set] = bound.flag
+
+		t, err := parseDateBound(bound.flag, bound.text, bound.nextDay)
+		if err != nil {
+			return err
+		}
+		*bound.set = &t
+	}
+
+	if view.DueWithin != "" {
+		end, err := parseDateBound("due-within", view.DueWithin, true)
+		if err != nil {
+			return err
+		}
+		y, m, d := time.Now().In(appLocation).Date()
+		start := time.Date(y, m, d, 0, 0, 0, 0, appLocation)
+		filter.Due = todo.TimeRange{From: &start, To: &end}
+	}
+
+	if view.Overdue {
+		filter.Overdue = true
+	}
+	if view.NoDue {
+		filter.NoDue = true
+	}
+	return nil
+}
+
+// parseDateBound reads the date given to a date filter flag. With nextDay
+// set, a day without a time is read as the start of the following day.
+func parseDateBound(flag, text string, nextDay bool) (time.Time, error) {
+	t, hasTime, err := dateParser().Parse(text)
+	if err != nil {
+		return time.Time{}, todo.InvalidInput(fmt.Errorf("invalid --%s: %w", flag, err))
+	}
+	if nextDay && !hasTime {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// viewFilter validates a view and returns the filter options it stands for
+func viewFilter(view storage.View) (todo.FilterOptions, error) {
+	filter := todo.FilterOptions{
+		ShowCompleted: view.Completed,
+		ShowPending:   view.Pending,
+		Search:        view.Search,
+		SearchRegex:   view.SearchRegex,
+		Project:       view.Project,
+		Blocked:       view.Blocked,
+		Tags:          view.Tags,
+		ExcludeTags:   view.ExcludeTags,
+		SortBy:        view.Sort,
+	}
+
+	// Use the configured sort unless the view has one, best matches first
+	// when searching
+	if filter.SortBy == "" {
+		filter.SortBy = appConfig.DefaultSort
+		if view.Search != "" {
+			filter.SortBy = "relevance," + filter.SortBy
+		}
+	}
+	if err := todo.ValidateSort(filter.SortBy); err != nil {
+		return filter, err
+	}
+
+	if view.GroupBy != "" {
+		if err := todo.ValidateGroupBy(view.GroupBy); err != nil {
+			return filter, err
 		}
+	}
 
-		// Build filter options
-		filter := todo.FilterOptions{
-			ShowCompleted: listCompleted,
-			ShowPending:   listPending,
-			Search:        listSearch,
-			SortBy:        listSort,
+	if view.SearchRegex && view.Search == "" {
+		return filter, todo.InvalidInput(fmt.Errorf("--regex needs a --search pattern"))
+	}
+	if view.Search != "" {
+		if err := todo.ValidateSearch(view.Search, view.SearchRegex); err != nil {
+			return filter, err
 		}
+	}
+
+	// Validate project filter
+	if _, err := todo.NormalizeProject(view.Project); err != nil {
+		return filter, err
+	}
 
-		// Validate and set priority filter
-		if listPriority != "" {
-			if !todo.ValidatePriority(listPriority) {
-				return fmt.Errorf("invalid priority '%s'. Valid options: low, medium, high", listPriority)
-			}
-			filter.Priority = todo.Priority(listPriority)
+	// Validate tag filters
+	for _, tag := range append(append([]string{}, view.Tags...), view.ExcludeTags...) {
+		if !todo.ValidateTag(tag) {
+			return filter, todo.InvalidInput(fmt.Errorf("invalid tag '%s'", tag))
 		}
+	}
 
-		// If neither completed nor pending is specified, show all
-		if !listCompleted && !listPending {
-			filter.ShowCompleted = true
-			filter.ShowPending = true
+	// Validate and set priority filter
+	if view.Priority != "" {
+		if !todo.ValidatePriority(string(view.Priority)) {
+			return filter, todo.InvalidInput(fmt.Errorf("invalid priority '%s'. Valid options: low, medium, high", view.Priority))
 		}
+		filter.Priority = todo.Priority(view.Priority)
+	}
 
-		// Get filtered tasks
-		tasks := manager.ListTasks(filter)
+	// Date filters
+	if err := applyDateFilters(&filter, view); err != nil {
+		return filter, err
+	}
 
-		if len(tasks) == 0 {
-			fmt.Println("📋 No tasks found matching your criteria.")
-			return nil
+	// Query filter
+	if view.Query != "" {
+		q, err := parseQuery(view.Query)
+		if err != nil {
+			return filter, err
 		}
+		filter.Where = queryFilter(q)
+	}
 
-		// Display tasks
-		return displayTasks(tasks)
-	},
+	// If neither completed nor pending is specified, show all
+	if !view.Completed && !view.Pending {
+		filter.ShowCompleted = true
+		filter.ShowPending = true
+	}
+
+	return filter, nil
+}
+
+// showTaskList displays tasks listed with filter, in headed sections if
+// groupBy is set, highlighting what a search matched. Sorting by project
+// alone also groups by project.
+func showTaskList(tasks []*todo.Task, filter todo.FilterOptions, groupBy string) error {
+	if structuredOutput() {
+		return writeOutput(newTaskListResult(tasks))
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("📋 No tasks found matching your criteria.")
+		return nil
+	}
+
+	if filter.Search != "" {
+		searchHighlight, _ = search.Compile(filter.Search, filter.SearchRegex)
+	}
+
+	if groupBy == "" && filter.SortBy == "project" {
+		groupBy = "project"
+	}
+	if groupBy != "" {
+		return displayTaskGroups(tasks, groupBy)
+	}
+	return displayTasks(tasks)
 }
 
 // displayTasks formats and displays the task list
@@ -79,22 +354,66 @@ func displayTasks(tasks []*todo.Task) error {
 	fmt.Printf("\n📋 Todo List (%d tasks)\n", len(tasks))
 	fmt.Println(strings.Repeat("─", 60))
 
-	for _, task := range tasks {
-		displayTask(task)
+	if listFlat {
+		for _, task := range tasks {
+			displayTask(task, 0)
+		}
+	} else {
+		for _, item := range manager.Tree(tasks) {
+			displayTask(item.Task, item.Depth)
+		}
+	}
+
+	fmt.Println()
+	return nil
+}
+
+// groupIcons are shown before the group headings of each kind of grouping
+var groupIcons = map[string]string{
+	"priority":   "🎯",
+	"project":    "📁",
+	"due-bucket": "📅",
+	"status":     "📌",
+}
+
+// displayTaskGroups displays tasks under a heading for each group, with the
+// number of tasks in it
+func displayTaskGroups(tasks []*todo.Task, groupBy string) error {
+	groups, err := manager.GroupTasks(tasks, groupBy)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n📋 Todo List (%d tasks)\n", len(tasks))
+
+	for _, group := range groups {
+		fmt.Println(strings.Repeat("─", 60))
+		color.New(color.FgBlue, color.Bold).Printf("%s %s (%d)\n", groupIcons[groupBy], group.Name, len(group.Tasks))
+		for _, task := range group.Tasks {
+			displayTask(task, 0)
+		}
 	}
 
 	fmt.Println()
 	return nil
 }
 
-// displayTask formats and displays a single task
-func displayTask(task *todo.Task) {
+// displayTask formats and displays a single task, indented by its depth in
+// the task tree
+func displayTask(task *todo.Task, depth int) {
+	indent := ""
+	if depth > 0 {
+		indent = strings.Repeat("   ", depth-1) + "└─ "
+	}
+
 	// Status icon
 	statusIcon := "⭕"
 	if task.Completed {
 		statusIcon = "✅"
-	} else if task.IsOverdue() {
+	} else if manager.IsOverdue(task) {
 		statusIcon = "🔴"
+	} else if manager.IsBlocked(task) {
+		statusIcon = "🔒"
 	}
 
 	// Priority color
@@ -112,24 +431,29 @@ func displayTask(task *todo.Task) {
 
 	// Task title (cross out if completed)
 	titleColor := color.New(color.Reset)
-	title := task.Title
+	prefix, suffix := "", ""
 	if task.Completed {
 		titleColor = color.New(color.Faint)
-		title = "✓ " + title
+		prefix = "✓ "
+	}
+
+	// Subtask progress
+	if done, total := manager.SubtaskProgress(task.ID); total > 0 {
+		suffix = fmt.Sprintf(" [%d/%d]", done, total)
 	}
 
 	// Format ID with padding
 	idStr := fmt.Sprintf("[%d]", task.ID)
 
 	// Build the main line
-	fmt.Printf("%s %-6s ", statusIcon, idStr)
-	titleColor.Printf("%-40s", title)
+	fmt.Printf("%s%s %-6s ", indent, statusIcon, idStr)
+	printTitle(titleColor, prefix, task.Title, suffix, max(40-len([]rune(indent)), 20))
 	priorityColor.Printf(" %s", strings.ToUpper(string(task.Priority)))
 
 	// Due date info
 	if task.DueDate != nil {
-		dueStr := task.DueDate.Format("2006-01-02")
-		if task.IsOverdue() && !task.Completed {
+		dueStr := formatDue(task)
+		if manager.IsOverdue(task) {
 			color.New(color.FgRed, color.Bold).Printf(" (DUE: %s)", dueStr)
 		} else {
 			color.New(color.FgCyan).Printf(" (Due: %s)", dueStr)
@@ -139,24 +463,56 @@ func displayTask(task *todo.Task) {
 	fmt.Println()
 
 	// Additional info line (created date, etc.)
-	createdStr := task.CreatedAt.Format("Jan 02, 2006")
-	color.New(color.Faint).Printf("       Created: %s", createdStr)
+	createdStr := formatDate(task.CreatedAt)
+	color.New(color.Faint).Printf("%s       Created: %s", strings.Repeat(" ", len([]rune(indent))), createdStr)
 	
-	if task.Completed {
-		updatedStr := task.UpdatedAt.Format("Jan 02, 2006")
-		color.New(color.Faint).Printf(" | Completed: %s", updatedStr)
+	if task.Completed && task.CompletedAt != nil {
+		completedStr := formatDate(*task.CompletedAt)
+		color.New(color.Faint).Printf(" | Completed: %s", completedStr)
+	}
+
+	if task.Project != "" {
+		color.New(color.FgBlue).Printf(" | @%s", task.Project)
+	}
+
+	if blockers := manager.Blockers(task); len(blockers) > 0 && !task.Completed {
+		color.New(color.FgYellow).Printf(" | Blocked by: %s", formatTaskIDs(blockers))
+	}
+
+	if len(task.Tags) > 0 {
+		color.New(color.FgMagenta).Printf(" | %s", task.FormatTags())
+	}
+
+	if task.IsRecurring() {
+		color.New(color.FgCyan).Printf(" | 🔁 %s", task.DescribeRecurrence())
 	}
 	
 	fmt.Println()
 	fmt.Println()
 }
 
-// showStats displays task statistics
-func showStats() error {
-	stats := manager.GetStats()
+// formatTaskIDs formats the IDs of tasks as "3, 5"
+func formatTaskIDs(tasks []*todo.Task) string {
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return formatIDs(ids, ", ")
+}
+
+// showStats displays statistics of tasks, noting the filters that selected
+// them if any
+func showStats(tasks []*todo.Task, filters string) error {
+	stats := manager.StatsFor(tasks)
+	if structuredOutput() {
+		return writeOutput(newStatsResult(stats, filters))
+	}
 	
 	fmt.Println("\n📊 Task Statistics")
 	fmt.Println(strings.Repeat("─", 30))
+	if filters != "" {
+		color.New(color.Faint).Printf("Filters: %s\n", filters)
+	}
 	
 	fmt.Printf("Total tasks:      %d\n", stats["total"])
 	fmt.Printf("Completed:        %d\n", stats["completed"])
@@ -176,20 +532,92 @@ func showStats() error {
 	}
 	
 	// Storage info
-	fmt.Printf("\nStorage location: %s\n", manager.GetStoragePath())
+	fmt.Printf("\nActive list:      %s\n", activeList)
+	fmt.Printf("Storage location: %s\n", manager.GetStoragePath())
 	
 	fmt.Println()
 	return nil
 }
 
+// printTitle prints a task title between prefix and suffix, padded to
+// width, highlighting the parts the search being listed matched
+func printTitle(c *color.Color, prefix, title, suffix string, width int) {
+	c.Print(prefix)
+
+	pos := 0
+	if searchHighlight != nil {
+		highlight := color.New(color.FgYellow, color.Bold, color.Underline)
+		for _, span := range searchHighlight.Highlight(title) {
+			c.Print(title[pos:span.Start])
+			highlight.Print(title[span.Start:span.End])
+			pos = span.End
+		}
+	}
+	c.Print(title[pos:])
+	c.Print(suffix)
+
+	if pad := width - utf8.RuneCountInString(prefix+title+suffix); pad > 0 {
+		fmt.Print(strings.Repeat(" ", pad))
+	}
+}
+
+// parseQuery parses a --query expression, showing where a syntax error is
+func parseQuery(text string) (*query.Query, error) {
+	q, err := query.New(dateParser()).Parse(text)
+	var syntaxErr *query.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return nil, todo.InvalidInput(fmt.Errorf("invalid query: %w\n  %s", err, strings.ReplaceAll(syntaxErr.Context(), "\n", "\n  ")))
+	}
+	return q, todo.InvalidInput(err)
+}
+
+// queryFilter returns a filter condition matching the tasks that meet q
+func queryFilter(q *query.Query) func(task *todo.Task) bool {
+	return func(task *todo.Task) bool {
+		return q.Match(task, manager)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 
 	// Add flags
-	listCmd.Flags().BoolVarP(&listCompleted, "completed", "c", false, "Show only completed tasks")
-	listCmd.Flags().BoolVarP(&listPending, "pending", "p", false, "Show only pending tasks")
-	listCmd.Flags().StringVar(&listPriority, "priority", "", "Filter by priority (low, medium, high)")
-	listCmd.Flags().StringVarP(&listSearch, "search", "s", "", "Search tasks by title")
-	listCmd.Flags().StringVar(&listSort, "sort", "id", "Sort by: id, priority, due, created")
+	addFilterFlags(listCmd)
 	listCmd.Flags().BoolVar(&listStats, "stats", false, "Show task statistics")
-}
\ No newline at end of file
+	listCmd.Flags().BoolVar(&listFlat, "flat", false, "Show subtasks as a flat list instead of a tree")
+}
+
+// addFilterFlags adds the flags that select and sort tasks, shared by list
+// and view save
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&listCompleted, "completed", "c", false, "Show only completed tasks")
+	cmd.Flags().BoolVarP(&listPending, "pending", "p", false, "Show only pending tasks")
+	cmd.Flags().StringVar(&listPriority, "priority", "", "Filter by priority (low, medium, high)")
+	cmd.Flags().StringVarP(&listSearch, "search", "s", "", "Search titles and tags: words (typos allowed) and \"quoted phrases\", best matches first")
+	cmd.Flags().BoolVar(&listRegex, "regex", false, "Treat --search as a regular expression")
+	cmd.Flags().StringVarP(&listProject, "project", "P", "", "Show only tasks in this project and its sub-projects")
+	cmd.Flags().StringSliceVarP(&listTags, "tag", "t", nil, "Show only tasks with this tag (repeatable)")
+	cmd.Flags().StringSliceVar(&listNoTags, "exclude-tag", nil, "Hide tasks with this tag (repeatable)")
+	cmd.Flags().StringVar(&listSort, "sort", "id", "Sort order: comma-separated keys ("+todo.SortFieldNames+"), - for descending, e.g. -priority,due")
+	cmd.Flags().StringVar(&listGroupBy, "group-by", "", "Show tasks in sections by: "+todo.GroupByNames)
+	cmd.Flags().BoolVar(&listBlocked, "blocked", false, "Show only tasks waiting on other tasks")
+	cmd.Flags().BoolVar(&listUnblocked, "unblocked", false, "Show only tasks not waiting on other tasks")
+	cmd.Flags().StringVarP(&listQuery, "query", "q", "", "Show only tasks matching a query, e.g. 'priority:high and not done'")
+	addDateFlags(cmd)
+}
+
+// addDateFlags adds the flags that filter tasks by date, shared by list,
+// view save and export
+func addDateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&listDueBefore, "due-before", "", "Show only tasks due before a date, e.g. fri")
+	cmd.Flags().StringVar(&listDueAfter, "due-after", "", "Show only tasks due after a date")
+	cmd.Flags().StringVar(&listDueWithin, "due-within", "", "Show only tasks due from today through a date or offset, e.g. 7d")
+	cmd.Flags().BoolVar(&listOverdue, "overdue", false, "Show only overdue tasks")
+	cmd.Flags().BoolVar(&listNoDue, "no-due", false, "Show only tasks without a due date")
+	cmd.Flags().StringVar(&listCreatedBefore, "created-before", "", "Show only tasks created before a date")
+	cmd.Flags().StringVar(&listCreatedAfter, "created-after", "", "Show only tasks created after a date")
+	cmd.Flags().StringVar(&listCreatedSince, "created-since", "", "Show only tasks created on or after a date, e.g. -7d")
+	cmd.Flags().StringVar(&listCompletedBefore, "completed-before", "", "Show only tasks completed before a date")
+	cmd.Flags().StringVar(&listCompletedAfter, "completed-after", "", "Show only tasks completed after a date")
+	cmd.Flags().StringVar(&listCompletedSince, "completed-since", "", "Show only tasks completed on or after a date, e.g. yesterday")
+}
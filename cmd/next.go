@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	nextLimit int
+)
+
+// nextCmd represents the next command
+var nextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Show the tasks you can work on now",
+	Long: `Show pending tasks that are not waiting on other tasks, most important
+first: by priority, then by due date.
+
+Examples:
+  todo next            # All tasks ready to work on
+  todo next -n 3       # The three most important ones`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tasks := manager.NextTasks()
+		if nextLimit > 0 && len(tasks) > nextLimit {
+			tasks = tasks[:nextLimit]
+		}
+
+		if structuredOutput() {
+			return writeOutput(newTaskListResult(tasks))
+		}
+
+		if len(tasks) == 0 {
+			fmt.Println("🎉 Nothing to do right now.")
+			return nil
+		}
+
+		fmt.Printf("\n👉 Next Up (%d tasks)\n", len(tasks))
+		fmt.Println(strings.Repeat("─", 60))
+
+		for _, task := range tasks {
+			displayTask(task, 0)
+		}
+
+		fmt.Println()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nextCmd)
+
+	// Add flags
+	nextCmd.Flags().IntVarP(&nextLimit, "limit", "n", 0, "Show at most this many tasks")
+}
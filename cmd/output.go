@@ -0,0 +1,526 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"todo-cli/internal/output"
+	"todo-cli/internal/todo"
+	"todo-cli/storage"
+)
+
+// Exit codes, so scripts can tell failures apart without reading messages
+const (
+	exitFailure      = 1 // any other failure, such as completing a completed task
+	exitInvalidInput = 2 // bad arguments, flags or values
+	exitNotFound     = 3 // no such task, backup or view
+	exitStorage      = 4 // the task list could not be read or written
+)
+
+// outputFormat is the --output format; text unless a script asked otherwise
+var outputFormat = string(output.Text)
+
+// structuredOutput reports whether results are written for scripts with
+// --output instead of as text
+func structuredOutput() bool {
+	return outputFormat != string(output.Text)
+}
+
+// writeOutput writes a command's result to stdout in the --output format
+func writeOutput(v any) error {
+	if err := output.Write(os.Stdout, output.Format(outputFormat), v); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+	return nil
+}
+
+// requireForce refuses to ask for confirmation when results are written for
+// scripts, as no one is there to answer
+func requireForce(force bool) error {
+	if structuredOutput() && !force && appConfig.Confirm {
+		return todo.InvalidInput(fmt.Errorf("--force is required with --output=%s", outputFormat))
+	}
+	return nil
+}
+
+// reportError writes err to stderr, as an error object if --output asks for
+// one, and returns the exit code for it
+func reportError(err error) int {
+	code, exitCode := classifyError(err)
+
+	// An unknown --output format is itself reported as text
+	if format, ferr := output.ParseFormat(outputFormat); ferr == nil && format != output.Text {
+		record := errorResult{Error: errorRecord{Code: code, Message: err.Error(), ExitCode: exitCode}}
+		if output.Write(os.Stderr, format, record) == nil {
+			return exitCode
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return exitCode
+}
+
+// classifyError returns the error code and exit code of err
+func classifyError(err error) (string, int) {
+	switch {
+	case errors.Is(err, todo.ErrTaskNotFound), errors.Is(err, storage.ErrBackupNotFound), errors.Is(err, errNoView):
+		return "not_found", exitNotFound
+	case errors.Is(err, todo.ErrInvalidInput), errors.Is(err, todo.ErrInvalidID):
+		return "invalid_input", exitInvalidInput
+	case errors.Is(err, todo.ErrStorage), errors.Is(err, storage.ErrConflict), errors.Is(err, storage.ErrLocked), errors.Is(err, storage.ErrSchemaTooNew):
+		return "storage", exitStorage
+	}
+	return "failed", exitFailure
+}
+
+// markUsageErrors makes the argument and flag errors of cmd and its
+// subcommands invalid input errors. Cobra reports them before any command
+// runs, so they cannot be marked where they happen.
+func markUsageErrors(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return usageError(cmd, validate(cmd, args))
+		}
+	}
+	cmd.SetFlagErrorFunc(usageError)
+
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// usageError marks err as invalid input. The usage text cobra prints after
+// such errors is left out when --output asks for an error object instead.
+func usageError(cmd *cobra.Command, err error) error {
+	if err != nil && structuredOutput() {
+		cmd.Root().SilenceUsage = true
+	}
+	return todo.InvalidInput(err)
+}
+
+// parseTaskID parses a task ID given as an argument
+func parseTaskID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, todo.InvalidInput(fmt.Errorf("invalid task ID '%s'. Please provide a valid number", arg))
+	}
+	return id, nil
+}
+
+// errorResult is an error as written by --output
+type errorResult struct {
+	Error errorRecord `json:"error" yaml:"error"`
+}
+
+// errorRecord describes a failed command
+type errorRecord struct {
+	Code     string `json:"code" yaml:"code"` // not_found, invalid_input, storage or failed
+	Message  string `json:"message" yaml:"message"`
+	ExitCode int    `json:"exit_code" yaml:"exit_code"`
+}
+
+func (r errorResult) Columns() []string { return []string{"code", "message", "exit_code"} }
+
+func (r errorResult) Rows() [][]string {
+	return [][]string{{r.Error.Code, r.Error.Message, strconv.Itoa(r.Error.ExitCode)}}
+}
+
+// taskRecord is a task as written by --output. Every field is always
+// present; times are in the configured time zone.
+type taskRecord struct {
+	ID          int        `json:"id" yaml:"id"`
+	Title       string     `json:"title" yaml:"title"`
+	Completed   bool       `json:"completed" yaml:"completed"`
+	Priority    string     `json:"priority" yaml:"priority"`
+	Due         *time.Time `json:"due" yaml:"due"` // start of the day for an all-day due date
+	DueAllDay   bool       `json:"due_all_day" yaml:"due_all_day"`
+	Project     string     `json:"project" yaml:"project"`
+	Tags        []string   `json:"tags" yaml:"tags"`
+	ParentID    int        `json:"parent_id" yaml:"parent_id"` // 0 for a top-level task
+	DependsOn   []int      `json:"depends_on" yaml:"depends_on"`
+	Recurrence  string     `json:"recurrence" yaml:"recurrence"` // RRULE, empty if the task does not repeat
+	Overdue     bool       `json:"overdue" yaml:"overdue"`
+	Blocked     bool       `json:"blocked" yaml:"blocked"`
+	CreatedAt   time.Time  `json:"created_at" yaml:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" yaml:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at" yaml:"completed_at"`
+}
+
+// taskColumns are the TSV columns of a task, in the order of taskRecord
+var taskColumns = []string{
+	"id", "title", "completed", "priority", "due", "due_all_day", "project", "tags", "parent_id",
+	"depends_on", "recurrence", "overdue", "blocked", "created_at", "updated_at", "completed_at",
+}
+
+// newTaskRecord returns the record of a task
+func newTaskRecord(task *todo.Task) taskRecord {
+	record := taskRecord{
+		ID:         task.ID,
+		Title:      task.Title,
+		Completed:  task.Completed,
+		Priority:   string(task.Priority),
+		DueAllDay:  task.DueAllDay,
+		Project:    task.Project,
+		Tags:       append([]string{}, task.Tags...),
+		ParentID:   task.ParentID,
+		DependsOn:  append([]int{}, task.DependsOn...),
+		Recurrence: task.Recurrence,
+		Overdue:    manager.IsOverdue(task),
+		Blocked:    manager.IsBlocked(task),
+		CreatedAt:  recordTime(task.CreatedAt),
+		UpdatedAt:  recordTime(task.UpdatedAt),
+	}
+	if task.DueDate != nil {
+		due := recordTime(task.DueIn(appLocation))
+		record.Due = &due
+	}
+	if task.CompletedAt != nil {
+		completed := recordTime(*task.CompletedAt)
+		record.CompletedAt = &completed
+	}
+	return record
+}
+
+// newTaskRecords returns the records of tasks
+func newTaskRecords(tasks []*todo.Task) []taskRecord {
+	records := make([]taskRecord, len(tasks))
+	for i, task := range tasks {
+		records[i] = newTaskRecord(task)
+	}
+	return records
+}
+
+// row returns the TSV row of a task
+func (r taskRecord) row() []string {
+	parent := ""
+	if r.ParentID != 0 {
+		parent = strconv.Itoa(r.ParentID)
+	}
+	return []string{
+		strconv.Itoa(r.ID),
+		r.Title,
+		strconv.FormatBool(r.Completed),
+		r.Priority,
+		formatRecordTime(r.Due),
+		strconv.FormatBool(r.DueAllDay),
+		r.Project,
+		strings.Join(r.Tags, ","),
+		parent,
+		formatIDs(r.DependsOn, ","),
+		r.Recurrence,
+		strconv.FormatBool(r.Overdue),
+		strconv.FormatBool(r.Blocked),
+		formatRecordTime(&r.CreatedAt),
+		formatRecordTime(&r.UpdatedAt),
+		formatRecordTime(r.CompletedAt),
+	}
+}
+
+// taskRows returns the TSV rows of tasks
+func taskRows(records []taskRecord) [][]string {
+	rows := make([][]string, len(records))
+	for i, record := range records {
+		rows[i] = record.row()
+	}
+	return rows
+}
+
+// recordTime returns t as written by --output: in the configured time zone,
+// to the second
+func recordTime(t time.Time) time.Time {
+	return t.In(appLocation).Truncate(time.Second)
+}
+
+// formatRecordTime formats a time for TSV, or returns "" for none
+func formatRecordTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// taskListResult is the tasks listed by list, view and next, or removed by
+// delete
+type taskListResult struct {
+	Tasks []taskRecord `json:"tasks" yaml:"tasks"`
+}
+
+func newTaskListResult(tasks []*todo.Task) taskListResult {
+	return taskListResult{Tasks: newTaskRecords(tasks)}
+}
+
+func (r taskListResult) Columns() []string { return taskColumns }
+func (r taskListResult) Rows() [][]string  { return taskRows(r.Tasks) }
+
+// taskResult is a task added or changed by a command
+type taskResult struct {
+	Task taskRecord `json:"task" yaml:"task"`
+}
+
+func newTaskResult(task *todo.Task) taskResult {
+	return taskResult{Task: newTaskRecord(task)}
+}
+
+func (r taskResult) Columns() []string { return taskColumns }
+func (r taskResult) Rows() [][]string  { return taskRows([]taskRecord{r.Task}) }
+
+// completeResult is what completing a task changed. As TSV it is the
+// completed task followed by the subtasks completed with it.
+type completeResult struct {
+	Task      taskRecord   `json:"task" yaml:"task"`
+	Subtasks  []taskRecord `json:"subtasks" yaml:"subtasks"`
+	Unblocked []taskRecord `json:"unblocked" yaml:"unblocked"`
+	Next      *taskRecord  `json:"next" yaml:"next"` // next occurrence of a recurring task
+}
+
+func newCompleteResult(result *todo.CompleteResult) completeResult {
+	r := completeResult{
+		Task:      newTaskRecord(result.Task),
+		Subtasks:  newTaskRecords(result.Subtasks),
+		Unblocked: newTaskRecords(result.Unblocked),
+	}
+	if result.Next != nil {
+		next := newTaskRecord(result.Next)
+		r.Next = &next
+	}
+	return r
+}
+
+func (r completeResult) Columns() []string { return taskColumns }
+func (r completeResult) Rows() [][]string {
+	return taskRows(append([]taskRecord{r.Task}, r.Subtasks...))
+}
+
+// statsResult is the statistics shown by list --stats
+type statsResult struct {
+	Stats   statsRecord `json:"stats" yaml:"stats"`
+	Filters string      `json:"filters" yaml:"filters"` // list flags the statistics are limited to
+	List    string      `json:"list" yaml:"list"`       // project, global or file
+	Storage string      `json:"storage" yaml:"storage"`
+}
+
+// statsRecord holds task counts
+type statsRecord struct {
+	Total     int `json:"total" yaml:"total"`
+	Completed int `json:"completed" yaml:"completed"`
+	Pending   int `json:"pending" yaml:"pending"`
+	Overdue   int `json:"overdue" yaml:"overdue"`
+	High      int `json:"high" yaml:"high"`
+	Medium    int `json:"medium" yaml:"medium"`
+	Low       int `json:"low" yaml:"low"`
+}
+
+func newStatsResult(stats map[string]int, filters string) statsResult {
+	return statsResult{
+		Stats: statsRecord{
+			Total:     stats["total"],
+			Completed: stats["completed"],
+			Pending:   stats["pending"],
+			Overdue:   stats["overdue"],
+			High:      stats["high"],
+			Medium:    stats["medium"],
+			Low:       stats["low"],
+		},
+		Filters: filters,
+		List:    activeList,
+		Storage: manager.GetStoragePath(),
+	}
+}
+
+func (r statsResult) Columns() []string {
+	return []string{"total", "completed", "pending", "overdue", "high", "medium", "low"}
+}
+
+func (r statsResult) Rows() [][]string {
+	s := r.Stats
+	return [][]string{{
+		strconv.Itoa(s.Total), strconv.Itoa(s.Completed), strconv.Itoa(s.Pending), strconv.Itoa(s.Overdue),
+		strconv.Itoa(s.High), strconv.Itoa(s.Medium), strconv.Itoa(s.Low),
+	}}
+}
+
+// tagsResult is the tags in use
+type tagsResult struct {
+	Tags []tagRecord `json:"tags" yaml:"tags"`
+}
+
+// tagRecord is a tag and the number of tasks carrying it
+type tagRecord struct {
+	Tag     string `json:"tag" yaml:"tag"`
+	Total   int    `json:"total" yaml:"total"`
+	Pending int    `json:"pending" yaml:"pending"`
+}
+
+func newTagsResult(counts []todo.TagCount) tagsResult {
+	r := tagsResult{Tags: []tagRecord{}}
+	for _, count := range counts {
+		r.Tags = append(r.Tags, tagRecord{Tag: count.Tag, Total: count.Total, Pending: count.Pending})
+	}
+	return r
+}
+
+func (r tagsResult) Columns() []string { return []string{"tag", "total", "pending"} }
+
+func (r tagsResult) Rows() [][]string {
+	rows := make([][]string, len(r.Tags))
+	for i, tag := range r.Tags {
+		rows[i] = []string{tag.Tag, strconv.Itoa(tag.Total), strconv.Itoa(tag.Pending)}
+	}
+	return rows
+}
+
+// changedResult is the number of tasks a command changed
+type changedResult struct {
+	Changed int `json:"changed" yaml:"changed"`
+}
+
+func (r changedResult) Columns() []string { return []string{"changed"} }
+func (r changedResult) Rows() [][]string  { return [][]string{{strconv.Itoa(r.Changed)}} }
+
+// projectsResult is the projects in use
+type projectsResult struct {
+	Projects []projectRecord `json:"projects" yaml:"projects"`
+}
+
+// projectRecord is a project and its task counts, including those of its
+// sub-projects
+type projectRecord struct {
+	Project   string `json:"project" yaml:"project"`
+	Depth     int    `json:"depth" yaml:"depth"` // number of parent projects
+	Pending   int    `json:"pending" yaml:"pending"`
+	Completed int    `json:"completed" yaml:"completed"`
+	Overdue   int    `json:"overdue" yaml:"overdue"`
+}
+
+func newProjectsResult(summaries []todo.ProjectSummary) projectsResult {
+	r := projectsResult{Projects: []projectRecord{}}
+	for _, summary := range summaries {
+		r.Projects = append(r.Projects, projectRecord{
+			Project:   summary.Project,
+			Depth:     summary.Depth,
+			Pending:   summary.Stats["pending"],
+			Completed: summary.Stats["completed"],
+			Overdue:   summary.Stats["overdue"],
+		})
+	}
+	return r
+}
+
+func (r projectsResult) Columns() []string {
+	return []string{"project", "depth", "pending", "completed", "overdue"}
+}
+
+func (r projectsResult) Rows() [][]string {
+	rows := make([][]string, len(r.Projects))
+	for i, p := range r.Projects {
+		rows[i] = []string{p.Project, strconv.Itoa(p.Depth), strconv.Itoa(p.Pending), strconv.Itoa(p.Completed), strconv.Itoa(p.Overdue)}
+	}
+	return rows
+}
+
+// backupRecord is a backup as written by --output
+type backupRecord struct {
+	ID        int       `json:"id" yaml:"id"` // 1 is the newest backup
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	Path      string    `json:"path" yaml:"path"`
+	Size      int64     `json:"size" yaml:"size"`
+}
+
+func newBackupRecord(backup storage.Backup) backupRecord {
+	return backupRecord{ID: backup.ID, Timestamp: recordTime(backup.Timestamp), Path: backup.Path, Size: backup.Size}
+}
+
+func (r backupRecord) row() []string {
+	return []string{strconv.Itoa(r.ID), formatRecordTime(&r.Timestamp), r.Path, strconv.FormatInt(r.Size, 10)}
+}
+
+var backupColumns = []string{"id", "timestamp", "path", "size"}
+
+// backupResult is a backup created or restored
+type backupResult struct {
+	Backup backupRecord `json:"backup" yaml:"backup"`
+}
+
+func (r backupResult) Columns() []string { return backupColumns }
+func (r backupResult) Rows() [][]string  { return [][]string{r.Backup.row()} }
+
+// backupsResult is the available backups, newest first
+type backupsResult struct {
+	Backups []backupRecord `json:"backups" yaml:"backups"`
+}
+
+func newBackupsResult(backups []storage.Backup) backupsResult {
+	r := backupsResult{Backups: []backupRecord{}}
+	for _, backup := range backups {
+		r.Backups = append(r.Backups, newBackupRecord(backup))
+	}
+	return r
+}
+
+func (r backupsResult) Columns() []string { return backupColumns }
+
+func (r backupsResult) Rows() [][]string {
+	rows := make([][]string, len(r.Backups))
+	for i, backup := range r.Backups {
+		rows[i] = backup.row()
+	}
+	return rows
+}
+
+// viewRecord is a saved view and the list flags it stands for
+type viewRecord struct {
+	Name    string `json:"name" yaml:"name"`
+	Filters string `json:"filters" yaml:"filters"`
+}
+
+var viewColumns = []string{"name", "filters"}
+
+// viewResult is a view saved or deleted
+type viewResult struct {
+	View viewRecord `json:"view" yaml:"view"`
+}
+
+func newViewResult(view storage.View) viewResult {
+	return viewResult{View: viewRecord{Name: view.Name, Filters: viewFlags(view)}}
+}
+
+func (r viewResult) Columns() []string { return viewColumns }
+func (r viewResult) Rows() [][]string  { return [][]string{{r.View.Name, r.View.Filters}} }
+
+// viewsResult is the saved views
+type viewsResult struct {
+	Views []viewRecord `json:"views" yaml:"views"`
+}
+
+func newViewsResult(views []storage.View) viewsResult {
+	r := viewsResult{Views: []viewRecord{}}
+	for _, view := range views {
+		r.Views = append(r.Views, newViewResult(view).View)
+	}
+	return r
+}
+
+func (r viewsResult) Columns() []string { return viewColumns }
+
+func (r viewsResult) Rows() [][]string {
+	rows := make([][]string, len(r.Views))
+	for i, view := range r.Views {
+		rows[i] = []string{view.Name, view.Filters}
+	}
+	return rows
+}
+
+// fileResult is a file written by export or init
+type fileResult struct {
+	Path   string `json:"path" yaml:"path"`
+	Format string `json:"format" yaml:"format"` // export format, or the backend of a new task list
+	Tasks  int    `json:"tasks" yaml:"tasks"`   // number of tasks written
+}
+
+func (r fileResult) Columns() []string { return []string{"path", "format", "tasks"} }
+func (r fileResult) Rows() [][]string  { return [][]string{{r.Path, r.Format, strconv.Itoa(r.Tasks)}} }
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"todo-cli/internal/todo"
+	"todo-cli/storage"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		code     string
+		exitCode int
+	}{
+		{"task not found", fmt.Errorf("failed to find task: %w", todo.ErrTaskNotFound), "not_found", exitNotFound},
+		{"backup not found", fmt.Errorf("failed to restore backup: %w", storage.ErrBackupNotFound), "not_found", exitNotFound},
+		{"view not found", errNoView, "not_found", exitNotFound},
+		{"invalid input", todo.InvalidInput(errors.New("invalid priority")), "invalid_input", exitInvalidInput},
+		{"invalid ID", fmt.Errorf("%w: abc", todo.ErrInvalidID), "invalid_input", exitInvalidInput},
+		{"storage failure", todo.StorageFailure(errors.New("disk full")), "storage", exitStorage},
+		{"conflict", fmt.Errorf("failed to save: %w", storage.ErrConflict), "storage", exitStorage},
+		{"locked", fmt.Errorf("failed to lock: %w", storage.ErrLocked), "storage", exitStorage},
+		{"schema too new", fmt.Errorf("failed to load: %w", storage.ErrSchemaTooNew), "storage", exitStorage},
+		{"anything else", errors.New("boom"), "failed", exitFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, exitCode := classifyError(tt.err)
+			if code != tt.code || exitCode != tt.exitCode {
+				t.Errorf("classifyError(%v) = %s, %d, want %s, %d", tt.err, code, exitCode, tt.code, tt.exitCode)
+			}
+		})
+	}
+}
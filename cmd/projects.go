@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// projectsCmd represents the projects command
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "Show task counts per project",
+	Long: `Show pending, completed and overdue task counts for every project.
+
+Project names are dot-separated, such as "work.backend.auth". The counts of
+a project include all of its sub-projects.
+
+Examples:
+  todo projects                      # Summary of every project
+  todo list --project=work           # Tasks in work and its sub-projects`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		summaries := manager.GetProjectSummaries()
+		if structuredOutput() {
+			return writeOutput(newProjectsResult(summaries))
+		}
+		if len(summaries) == 0 {
+			fmt.Println("📁 No projects in use. Add a task to one with: todo add \"Task title\" --project=name")
+			return nil
+		}
+
+		fmt.Printf("\n📁 Projects (%d)\n", len(summaries))
+		fmt.Println(strings.Repeat("─", 60))
+		fmt.Printf("%-32s %8s %10s %8s\n", "Project", "Pending", "Completed", "Overdue")
+
+		for _, summary := range summaries {
+			// Show only the last level, indented under its parent
+			levels := strings.Split(summary.Project, ".")
+			name := strings.Repeat("  ", summary.Depth) + levels[len(levels)-1]
+
+			fmt.Printf("%-32s %8d %10d ", name, summary.Stats["pending"], summary.Stats["completed"])
+			if summary.Stats["overdue"] > 0 {
+				color.New(color.FgRed, color.Bold).Printf("%8d\n", summary.Stats["overdue"])
+			} else {
+				fmt.Printf("%8d\n", summary.Stats["overdue"])
+			}
+		}
+
+		fmt.Println()
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(projectsCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// reopenCmd represents the reopen command
+var reopenCmd = &cobra.Command{
+	Use:   "reopen [task_id]",
+	Short: "Mark a completed task as pending again",
+	Long: `Undo completing a task by providing its ID.
+
+Reopening a subtask also reopens its parent tasks.
+
+Examples:
+  todo reopen 1        # Mark task with ID 1 as pending again
+
+You can find completed task IDs by running: todo list --completed`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Parse task ID
+		taskID, err := parseTaskID(args[0])
+		if err != nil {
+			return err
+		}
+
+		// Reopen the task
+		task, err := manager.ReopenTask(taskID)
+		if err != nil {
+			return fmt.Errorf("failed to reopen task: %w", err)
+		}
+
+		if structuredOutput() {
+			return writeOutput(newTaskResult(task))
+		}
+
+		// Display success message
+		fmt.Printf("↩️  Task reopened successfully!\n")
+		fmt.Printf("   ID: %d\n", task.ID)
+		fmt.Printf("   Title: %s\n", task.Title)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reopenCmd)
+}
@@ -3,16 +3,36 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"todo-cli/internal/config"
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/output"
 	"todo-cli/internal/todo"
+	"todo-cli/storage"
 )
 
 var (
-	manager *todo.Manager
-	cfgFile string
+	manager        *todo.Manager
+	appConfig      = config.Default()
+	appLocation    = time.Local // configured time zone for reading and showing dates
+	cfgFile        string
+	storageBackend string
+	lockTimeout    time.Duration
+	backupKeep     int
+	autoBackup     bool
+	listFile       string
+	useGlobal      bool
+	activeList     string
+	taskFilePath   string // task list in use; saved views are kept next to it
 )
 
+// annotationWithoutTasks marks commands that still run when the task list
+// cannot be loaded
+const annotationWithoutTasks = "without-tasks"
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "todo",
@@ -25,27 +45,216 @@ Examples:
   todo list --completed
   todo complete 1
   todo delete 2`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		format, err := output.ParseFormat(outputFormat)
+		if err != nil {
+			return todo.InvalidInput(err)
+		}
+		outputFormat = string(format)
+		if structuredOutput() {
+			cmd.Root().SilenceUsage = true
+		}
+
+		// Load configuration; flags given on the command line take precedence
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return err
+		}
+		applyConfig(cmd, cfg)
+
+		// Pick the task list: --file, a project list, or the global list
+		path, scope, err := resolveStoragePath(storageBackend)
+		if err != nil {
+			return err
+		}
+		activeList = scope
+		taskFilePath = path
+
 		// Initialize the task manager
-		manager = todo.NewManager()
-		if err := manager.LoadTasks(); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Failed to load tasks: %v\n", err)
+		backend, err := newBackend(storageBackend, path)
+		if err != nil {
+			return err
+		}
+
+		manager = todo.NewManagerWithBackend(backend)
+		manager.SetAutoBackup(autoBackup)
+		manager.SetLocation(appLocation)
+		if err := loadTasks(backend); err != nil {
+			// Commands that can repair a list, such as backup restore, run anyway
+			if cmd.Annotations[annotationWithoutTasks] == "" {
+				cmd.Root().SilenceUsage = true
+				return err
+			}
+			if !structuredOutput() {
+				fmt.Fprintf(os.Stderr, "Warning: Failed to load tasks: %v\n", err)
+			}
 		}
+		return nil
 	},
 }
 
+// loadTasks reads the task list before a command runs. SQLite answers
+// queries itself, so its tasks are read only when a command needs them;
+// opening the database still reports a damaged or too new one up front.
+func loadTasks(backend storage.Backend) error {
+	if ss, ok := backend.(*storage.SQLiteStorage); ok {
+		if err := ss.Open(); err != nil {
+			return todo.StorageFailure(fmt.Errorf("failed to load tasks: %w", err))
+		}
+		return nil
+	}
+	return manager.LoadTasks()
+}
+
+// applyConfig makes cfg the active configuration and fills in every global
+// flag that was not set explicitly
+func applyConfig(cmd *cobra.Command, cfg *config.Config) {
+	appConfig = cfg
+	appLocation = cfg.Location()
+
+	flags := cmd.Flags()
+	if !flags.Changed("backend") {
+		storageBackend = cfg.Backend
+	}
+	if !flags.Changed("lock-timeout") {
+		lockTimeout = cfg.LockTimeout
+	}
+	if !flags.Changed("backup-retention") {
+		backupKeep = cfg.BackupRetention
+	}
+	if !flags.Changed("auto-backup") {
+		autoBackup = cfg.AutoBackup
+	}
+
+	if !cfg.Color {
+		color.NoColor = true
+	}
+}
+
+// resolveStoragePath returns the task list to use and a short description of
+// why it was chosen. An explicit --file wins; otherwise a project list
+// (.todo/ in the working directory or a parent) is used unless --global is
+// given, falling back to the configured or default global list.
+func resolveStoragePath(backendName string) (string, string, error) {
+	if listFile != "" && useGlobal {
+		return "", "", todo.InvalidInput(fmt.Errorf("--file and --global cannot be used together"))
+	}
+
+	if listFile != "" {
+		return listFile, "file", nil
+	}
+
+	fileName := backendFileName(backendName)
+
+	if !useGlobal {
+		if cwd, err := os.Getwd(); err == nil {
+			if path, ok := storage.FindProjectFile(cwd, fileName); ok {
+				return path, "project", nil
+			}
+		}
+	}
+
+	if appConfig.StoragePath != "" {
+		return appConfig.StoragePath, "global", nil
+	}
+	return storage.DefaultFilePath(fileName), "global", nil
+}
+
+// backendFileName returns the file name a backend stores its tasks in
+func backendFileName(name string) string {
+	if name == "sqlite" {
+		return storage.DefaultSQLiteFileName
+	}
+	return storage.DefaultFileName
+}
+
+// newBackend creates the storage backend selected with --backend. An empty
+// path uses the backend's default location.
+func newBackend(name, path string) (storage.Backend, error) {
+	switch name {
+	case "", "json":
+		fs := storage.NewFileStorage(path)
+		fs.SetLockTimeout(lockTimeout)
+		fs.SetBackupRetention(backupKeep)
+		return fs, nil
+	case "sqlite":
+		ss := storage.NewSQLiteStorage(path)
+		ss.SetLockTimeout(lockTimeout)
+		ss.SetBackupRetention(backupKeep)
+		return ss, nil
+	default:
+		return nil, todo.InvalidInput(fmt.Errorf("unsupported backend '%s'. Supported backends: json, sqlite", name))
+	}
+}
+
+// dateParser returns the parser for dates typed by the user: relative to the
+// current time, in the configured time zone, also accepting the configured
+// format
+func dateParser() *dateparse.Parser {
+	return &dateparse.Parser{
+		Now:      time.Now,
+		Location: appLocation,
+		Layouts:  []string{appConfig.DateFormat},
+	}
+}
+
+// formatDate formats a date for display using the configured date format
+// and time zone
+func formatDate(t time.Time) string {
+	return t.In(appLocation).Format(appConfig.DateFormat)
+}
+
+// formatDue formats a task's due date for display: the day of an all-day
+// due date, or the day and time of a timed one in the configured time zone
+func formatDue(task *todo.Task) string {
+	due := task.DueIn(appLocation)
+	if task.DueAllDay {
+		return due.Format(appConfig.DateFormat)
+	}
+	return due.Format(appConfig.DateFormat + " 15:04")
+}
+
+// confirmed asks the user to type 'yes' unless confirmations are turned off
+// in the configuration or force is set
+func confirmed(force bool, prompt string) bool {
+	if force || !appConfig.Confirm {
+		return true
+	}
+
+	fmt.Print(prompt)
+
+	var confirmation string
+	fmt.Scanln(&confirmation)
+
+	return confirmation == "yes" || confirmation == "y" || confirmation == "YES" || confirmation == "Y"
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The exit code tells what kind of error stopped the command, if any.
 func Execute() {
+	markUsageErrors(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(reportError(err))
 	}
 }
 
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.todo/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&storageBackend, "backend", "json", "Storage backend (json, sqlite)")
+	rootCmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", storage.DefaultLockTimeout, "How long to wait for another todo process to release the list")
+	rootCmd.PersistentFlags().IntVar(&backupKeep, "backup-retention", storage.DefaultBackupRetention, "Number of backups to keep (0 keeps all)")
+	rootCmd.PersistentFlags().BoolVar(&autoBackup, "auto-backup", false, "Take a backup before destructive commands such as delete")
+	rootCmd.PersistentFlags().StringVar(&listFile, "file", "", "Use this task list file instead of the project or global list")
+	rootCmd.PersistentFlags().BoolVarP(&useGlobal, "global", "g", false, "Use the global task list even inside a project")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", string(output.Text), "Output format for scripts ("+output.FormatNames+")")
+
+	// Errors are reported by Execute, as text or as an --output error object
+	rootCmd.SilenceErrors = true
+
+	// Arguments that are not a command are reported as invalid input
+	rootCmd.Args = cobra.NoArgs
 	
 	// Add version flag
 	rootCmd.Flags().BoolP("version", "v", false, "Show version information")
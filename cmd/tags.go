@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeInto string
+)
+
+// tagsCmd represents the tags command
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List tags in use",
+	Long: `List every tag in use with the number of tasks carrying it.
+
+Tags are added with +tag words in 'todo add' and used to filter with
+'todo list --tag'.
+
+Examples:
+  todo tags                          # List tags with task counts
+  todo tags rename home house        # Rename +home to +house
+  todo tags merge bug defect --into=bugs`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		counts := manager.GetTagCounts()
+		if structuredOutput() {
+			return writeOutput(newTagsResult(counts))
+		}
+		if len(counts) == 0 {
+			fmt.Println("🏷  No tags in use. Add one with: todo add \"Task title +tag\"")
+			return nil
+		}
+
+		fmt.Printf("\n🏷  Tags (%d)\n", len(counts))
+		fmt.Println(strings.Repeat("─", 40))
+
+		for _, count := range counts {
+			fmt.Printf("+%-24s %3d tasks (%d pending)\n", count.Tag, count.Total, count.Pending)
+		}
+
+		fmt.Println()
+		return nil
+	},
+}
+
+// tagsRenameCmd represents the tags rename command
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename [old_tag] [new_tag]",
+	Short: "Rename a tag on every task",
+	Long: `Rename a tag on every task that has it. Tasks that already have the
+new tag simply lose the old one.
+
+Example:
+  todo tags rename home house`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changed, err := manager.RenameTag(args[0], args[1])
+		if err != nil {
+			return fmt.Errorf("failed to rename tag: %w", err)
+		}
+
+		if structuredOutput() {
+			return writeOutput(changedResult{Changed: changed})
+		}
+
+		fmt.Printf("🏷  Renamed tag on %d task(s)\n", changed)
+		return nil
+	},
+}
+
+// tagsMergeCmd represents the tags merge command
+var tagsMergeCmd = &cobra.Command{
+	Use:   "merge [tag]... --into=[tag]",
+	Short: "Merge several tags into one",
+	Long: `Replace each of the given tags with the --into tag on every task.
+
+Example:
+  todo tags merge bug defect --into=bugs`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changed, err := manager.MergeTags(args, mergeInto)
+		if err != nil {
+			return fmt.Errorf("failed to merge tags: %w", err)
+		}
+
+		if structuredOutput() {
+			return writeOutput(changedResult{Changed: changed})
+		}
+
+		fmt.Printf("🏷  Merged tags on %d task(s)\n", changed)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+	tagsCmd.AddCommand(tagsRenameCmd)
+	tagsCmd.AddCommand(tagsMergeCmd)
+
+	// Add flags
+	tagsMergeCmd.Flags().StringVar(&mergeInto, "into", "", "Tag to merge into")
+	tagsMergeCmd.MarkFlagRequired("into")
+}
@@ -4,10 +4,13 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
+	"todo-cli/internal/quickadd"
 	"todo-cli/internal/todo"
+	"todo-cli/storage"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -26,10 +29,14 @@ This provides a simple, menu-driven interface to:
   - Delete tasks
   - View statistics
   - Export tasks
+  - Open saved views (see 'todo view')
 
 Examples:
   todo ui              # Launch interactive UI`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if structuredOutput() {
+			return todo.InvalidInput(fmt.Errorf("the interactive UI has no --output=%s", outputFormat))
+		}
 		return runInteractiveUI()
 	},
 }
@@ -47,7 +54,12 @@ func runInteractiveUI() error {
 	showWelcomeBanner()
 
 	for {
-		showMainMenu()
+		// Reload saved views each time, as they may change in another terminal
+		views, err := loadViews()
+		if err != nil {
+			color.Red("❌ %v", err)
+		}
+		showMainMenu(views)
 
 		choice, err := reader.ReadString('\n')
 		if err != nil {
@@ -77,6 +89,10 @@ func runInteractiveUI() error {
 		case "c", "C":
 			clearScreen()
 		default:
+			if view := pickView(choice, views); view != nil {
+				viewSavedUI(view)
+				continue
+			}
 			color.Red("❌ Invalid option. Please try again.")
 			pause()
 		}
@@ -102,8 +118,8 @@ func showWelcomeBanner() {
 	fmt.Println()
 }
 
-// showMainMenu displays the main menu
-func showMainMenu() {
+// showMainMenu displays the main menu, with an entry for each saved view
+func showMainMenu(views []storage.View) {
 	green := color.New(color.FgGreen, color.Bold)
 	white := color.New(color.FgWhite)
 
@@ -119,6 +135,13 @@ func showMainMenu() {
 	white.Println("  6️⃣  Export Tasks")
 	white.Println("  7️⃣  Backup Tasks")
 	white.Println("  8️⃣  Exit (or press 'q')")
+	if len(views) > 0 {
+		fmt.Println()
+		white.Println("  🔖 Saved views:")
+		for i, view := range views {
+			white.Printf("  v%-3d %s\n", i+1, view.Name)
+		}
+	}
 	fmt.Println()
 	white.Println("  💡 Type 'c' to clear screen")
 	fmt.Println(strings.Repeat("─", 62))
@@ -142,7 +165,12 @@ func viewTasks() {
 		SortBy:        "id",
 	}
 
-	tasks := manager.ListTasks(filter)
+	tasks, err := manager.ListTasks(filter)
+	if err != nil {
+		color.Red("  ❌ Failed to list tasks: %v\n", err)
+		pause()
+		return
+	}
 
 	if len(tasks) == 0 {
 		color.Yellow("\n  ℹ️  No tasks found. Add your first task to get started!\n")
@@ -153,18 +181,72 @@ func viewTasks() {
 	pause()
 }
 
-// displayTasksUI formats and displays tasks in UI mode
+// pickView returns the saved view chosen with a menu entry such as "v2", or
+// nil if choice is not one
+func pickView(choice string, views []storage.View) *storage.View {
+	number, ok := strings.CutPrefix(strings.ToLower(choice), "v")
+	if !ok {
+		return nil
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 || n > len(views) {
+		return nil
+	}
+	return &views[n-1]
+}
+
+// viewSavedUI displays the tasks shown by a saved view
+func viewSavedUI(view *storage.View) {
+	clearScreen()
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	cyan.Println("\n╔════════════════════════════════════════════════════════════╗")
+	cyan.Printf("║  🔖 VIEW: %-49s║\n", view.Name)
+	cyan.Println("╚════════════════════════════════════════════════════════════╝")
+	if flags := viewFlags(*view); flags != "" {
+		color.New(color.Faint).Printf("  %s\n", flags)
+	}
+	fmt.Println()
+
+	filter, err := viewFilter(*view)
+	if err != nil {
+		color.Red("  ❌ View '%s' is invalid: %v\n", view.Name, err)
+		pause()
+		return
+	}
+
+	tasks, err := manager.ListTasks(filter)
+	if err != nil {
+		color.Red("  ❌ Failed to list tasks: %v\n", err)
+		pause()
+		return
+	}
+	if len(tasks) == 0 {
+		color.Yellow("\n  ℹ️  No tasks match this view.\n")
+	} else {
+		displayTasksUI(tasks)
+	}
+
+	pause()
+}
+
+// displayTasksUI formats and displays tasks in UI mode, with subtasks nested
+// under their parents
 func displayTasksUI(tasks []*todo.Task) {
-	for _, task := range tasks {
+	for _, item := range manager.Tree(tasks) {
+		task := item.Task
+		indent := strings.Repeat("   ", item.Depth)
 		// Status indicator
 		statusIcon := "⭕"
 		statusColor := color.New(color.FgYellow)
 		if task.Completed {
 			statusIcon = "✅"
 			statusColor = color.New(color.FgGreen)
-		} else if task.IsOverdue() {
+		} else if manager.IsOverdue(task) {
 			statusIcon = "🔴"
 			statusColor = color.New(color.FgRed)
+		} else if manager.IsBlocked(task) {
+			statusIcon = "🔒"
 		}
 
 		// Priority color
@@ -183,7 +265,10 @@ func displayTasksUI(tasks []*todo.Task) {
 		}
 
 		// Display task
-		fmt.Print("  ")
+		fmt.Print("  " + indent)
+		if item.Depth > 0 {
+			fmt.Print("└─ ")
+		}
 		statusColor.Print(statusIcon)
 		fmt.Printf(" [%d] ", task.ID)
 
@@ -193,21 +278,41 @@ func displayTasksUI(tasks []*todo.Task) {
 			color.New(color.FgWhite, color.Bold).Print(task.Title)
 		}
 
+		if done, total := manager.SubtaskProgress(task.ID); total > 0 {
+			color.New(color.FgCyan).Printf(" [%d/%d]", done, total)
+		}
+
 		fmt.Print(" | ")
 		priorityColor.Print(priorityText)
 
 		if task.DueDate != nil {
-			if task.IsOverdue() && !task.Completed {
-				color.New(color.FgRed, color.Bold).Printf(" | ⏰ OVERDUE: %s", task.DueDate.Format("Jan 02"))
+			if manager.IsOverdue(task) {
+				color.New(color.FgRed, color.Bold).Printf(" | ⏰ OVERDUE: %s", formatDue(task))
 			} else {
-				color.New(color.FgCyan).Printf(" | 📅 Due: %s", task.DueDate.Format("Jan 02"))
+				color.New(color.FgCyan).Printf(" | 📅 Due: %s", formatDue(task))
 			}
 		}
 
 		fmt.Println()
 
-		if task.Completed {
-			color.New(color.Faint).Printf("      ✓ Completed on %s\n", task.UpdatedAt.Format("Jan 02, 2006"))
+		if task.Project != "" {
+			color.New(color.FgBlue).Printf("      %s📁 %s\n", indent, task.Project)
+		}
+
+		if len(task.Tags) > 0 {
+			color.New(color.FgMagenta).Printf("      %s🏷  %s\n", indent, task.FormatTags())
+		}
+
+		if blockers := manager.Blockers(task); len(blockers) > 0 && !task.Completed {
+			color.New(color.FgYellow).Printf("      %s🔒 Blocked by: %s\n", indent, formatTaskIDs(blockers))
+		}
+
+		if task.IsRecurring() {
+			color.New(color.FgCyan).Printf("      %s🔁 %s\n", indent, task.DescribeRecurrence())
+		}
+
+		if task.Completed && task.CompletedAt != nil {
+			color.New(color.Faint).Printf("      %s✓ Completed on %s\n", indent, formatDate(*task.CompletedAt))
 		}
 
 		fmt.Println()
@@ -225,7 +330,7 @@ func addTaskUI(reader *bufio.Reader) {
 	fmt.Println()
 
 	// Get task title
-	fmt.Print("  📝 Task Title: ")
+	fmt.Print("  📝 Task Title (inline: !high +tag @project due:fri rec:weekly): ")
 	title, _ := reader.ReadString('\n')
 	title = strings.TrimSpace(title)
 
@@ -235,43 +340,29 @@ func addTaskUI(reader *bufio.Reader) {
 		return
 	}
 
-	// Get priority
-	fmt.Println("\n  🎯 Priority:")
-	fmt.Println("     1. Low")
-	fmt.Println("     2. Medium (default)")
-	fmt.Println("     3. High")
-	fmt.Print("\n  Choose (1-3, or press Enter for default): ")
-
-	priorityChoice, _ := reader.ReadString('\n')
-	priorityChoice = strings.TrimSpace(priorityChoice)
+	// Take inline fields out of the title; only ask for what is missing
+	parsed, err := quickadd.New(dateParser()).Parse(title)
+	if err != nil {
+		color.Red("\n  ❌ %v", err)
+		pause()
+		return
+	}
+	opts := parsed.AddOptions()
 
-	var priority todo.Priority
-	switch priorityChoice {
-	case "1":
-		priority = todo.PriorityLow
-	case "3":
-		priority = todo.PriorityHigh
-	default:
-		priority = todo.PriorityMedium
+	if len(parsed.Tokens) > 0 {
+		color.Cyan("\n  🔎 Parsed from title: %s", strings.Join(parsed.Tokens, " "))
 	}
 
-	// Get due date
-	fmt.Print("\n  📅 Due Date (YYYY-MM-DD or press Enter to skip): ")
-	dueDateStr, _ := reader.ReadString('\n')
-	dueDateStr = strings.TrimSpace(dueDateStr)
+	if opts.Priority == "" {
+		opts.Priority = askPriorityUI(reader)
+	}
 
-	var dueDate *time.Time
-	if dueDateStr != "" {
-		parsedDate, err := time.Parse("2006-01-02", dueDateStr)
-		if err != nil {
-			color.Red("\n  ⚠️  Invalid date format. Task will be added without due date.")
-		} else {
-			dueDate = &parsedDate
-		}
+	if opts.DueDate == nil {
+		opts.DueDate, opts.DueAllDay = askDueDateUI(reader)
 	}
 
 	// Add the task
-	task, err := manager.AddTask(title, priority, dueDate)
+	task, err := manager.AddTaskWithOptions(parsed.Title, opts)
 	if err != nil {
 		color.Red("\n  ❌ Failed to add task: %v", err)
 		pause()
@@ -286,13 +377,64 @@ func addTaskUI(reader *bufio.Reader) {
 	fmt.Printf("     Title: %s\n", task.Title)
 	fmt.Printf("     Priority: %s\n", strings.ToUpper(string(task.Priority)))
 	if task.DueDate != nil {
-		fmt.Printf("     Due: %s\n", task.DueDate.Format("2006-01-02"))
+		fmt.Printf("     Due: %s\n", formatDue(task))
+	}
+	if task.Project != "" {
+		fmt.Printf("     Project: %s\n", task.Project)
+	}
+	if len(task.Tags) > 0 {
+		fmt.Printf("     Tags: %s\n", task.FormatTags())
+	}
+	if task.IsRecurring() {
+		fmt.Printf("     Repeats: %s\n", task.DescribeRecurrence())
 	}
 	fmt.Println()
 
 	pause()
 }
 
+// askPriorityUI asks for a task priority, defaulting to the configured one
+func askPriorityUI(reader *bufio.Reader) todo.Priority {
+	fmt.Println("\n  🎯 Priority:")
+	fmt.Println("     1. Low")
+	fmt.Println("     2. Medium")
+	fmt.Println("     3. High")
+	fmt.Printf("\n  Choose (1-3, or press Enter for default: %s): ", appConfig.DefaultPriority)
+
+	priorityChoice, _ := reader.ReadString('\n')
+	priorityChoice = strings.TrimSpace(priorityChoice)
+
+	switch priorityChoice {
+	case "1":
+		return todo.PriorityLow
+	case "2":
+		return todo.PriorityMedium
+	case "3":
+		return todo.PriorityHigh
+	default:
+		return todo.Priority(appConfig.DefaultPriority)
+	}
+}
+
+// askDueDateUI asks for an optional due date and reports whether it is an
+// all-day due date
+func askDueDateUI(reader *bufio.Reader) (*time.Time, bool) {
+	fmt.Print("\n  📅 Due Date (e.g. 2025-10-20, tomorrow, fri 5pm, in 3 days; Enter to skip): ")
+	dueDateStr, _ := reader.ReadString('\n')
+	dueDateStr = strings.TrimSpace(dueDateStr)
+
+	if dueDateStr == "" {
+		return nil, false
+	}
+
+	dueDate, allDay, err := parseDueDate(dueDateStr)
+	if err != nil {
+		color.Red("\n  ⚠️  Invalid date format. Task will be added without due date.")
+		return nil, false
+	}
+	return &dueDate, allDay
+}
+
 // completeTaskUI handles completing a task through UI
 func completeTaskUI(reader *bufio.Reader) {
 	clearScreen()
@@ -310,7 +452,12 @@ func completeTaskUI(reader *bufio.Reader) {
 		SortBy:        "id",
 	}
 
-	tasks := manager.ListTasks(filter)
+	tasks, err := manager.ListTasks(filter)
+	if err != nil {
+		color.Red("  ❌ Failed to list tasks: %v\n", err)
+		pause()
+		return
+	}
 
 	if len(tasks) == 0 {
 		color.Yellow("  ℹ️  No pending tasks to complete!\n")
@@ -318,13 +465,13 @@ func completeTaskUI(reader *bufio.Reader) {
 		return
 	}
 
-	fmt.Println("  📋 Pending Tasks:\n")
+	fmt.Print("  📋 Pending Tasks:\n\n")
 	displayTasksUI(tasks)
 
 	fmt.Print("\n  👉 Enter Task ID to complete (or 0 to cancel): ")
 
 	var taskID int
-	_, err := fmt.Fscanf(reader, "%d\n", &taskID)
+	_, err = fmt.Fscanf(reader, "%d\n", &taskID)
 	if err != nil {
 		color.Red("\n  ❌ Invalid input!")
 		pause()
@@ -337,18 +484,28 @@ func completeTaskUI(reader *bufio.Reader) {
 		return
 	}
 
-	task, err := manager.CompleteTask(taskID)
+	result, err := manager.CompleteTask(taskID)
 	if err != nil {
 		color.Red("\n  ❌ Failed to complete task: %v", err)
 		pause()
 		return
 	}
+	task := result.Task
 
 	fmt.Println()
 	color.Green("  ✅ Task completed successfully!")
 	fmt.Printf("\n     ID: %d\n", task.ID)
 	fmt.Printf("     Title: %s\n", task.Title)
-	fmt.Printf("     Completed at: %s\n", task.UpdatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("     Completed at: %s\n", task.CompletedAt.In(appLocation).Format("2006-01-02 15:04:05"))
+	if len(result.Subtasks) > 0 {
+		fmt.Printf("     Also completed %d subtask(s)\n", len(result.Subtasks))
+	}
+	for _, unblocked := range result.Unblocked {
+		color.Cyan("     🔓 Unblocked: [%d] %s", unblocked.ID, unblocked.Title)
+	}
+	if next := result.Next; next != nil {
+		color.Cyan("     🔁 Next occurrence: [%d] due %s", next.ID, formatDue(next))
+	}
 	fmt.Println()
 
 	pause()
@@ -371,7 +528,12 @@ func deleteTaskUI(reader *bufio.Reader) {
 		SortBy:        "id",
 	}
 
-	tasks := manager.ListTasks(filter)
+	tasks, err := manager.ListTasks(filter)
+	if err != nil {
+		color.Red("  ❌ Failed to list tasks: %v\n", err)
+		pause()
+		return
+	}
 
 	if len(tasks) == 0 {
 		color.Yellow("  ℹ️  No tasks to delete!\n")
@@ -379,13 +541,13 @@ func deleteTaskUI(reader *bufio.Reader) {
 		return
 	}
 
-	fmt.Println("  📋 All Tasks:\n")
+	fmt.Print("  📋 All Tasks:\n\n")
 	displayTasksUI(tasks)
 
 	fmt.Print("\n  👉 Enter Task ID to delete (or 0 to cancel): ")
 
 	var taskID int
-	_, err := fmt.Fscanf(reader, "%d\n", &taskID)
+	_, err = fmt.Fscanf(reader, "%d\n", &taskID)
 	if err != nil {
 		color.Red("\n  ❌ Invalid input!")
 		pause()
@@ -406,23 +568,31 @@ func deleteTaskUI(reader *bufio.Reader) {
 		return
 	}
 
-	// Confirmation
-	fmt.Println()
-	color.Yellow("  ⚠️  Are you sure you want to delete this task?")
-	fmt.Printf("\n     ID: %d\n", task.ID)
-	fmt.Printf("     Title: %s\n", task.Title)
-	fmt.Print("\n  Type 'yes' to confirm: ")
+	subtasks := manager.GetDescendants(taskID)
 
-	confirmation, _ := reader.ReadString('\n')
-	confirmation = strings.TrimSpace(strings.ToLower(confirmation))
+	// Confirmation, unless turned off in the configuration. Deleting the
+	// subtasks along with the task is always confirmed.
+	if appConfig.Confirm || len(subtasks) > 0 {
+		fmt.Println()
+		color.Yellow("  ⚠️  Are you sure you want to delete this task?")
+		fmt.Printf("\n     ID: %d\n", task.ID)
+		fmt.Printf("     Title: %s\n", task.Title)
+		if len(subtasks) > 0 {
+			fmt.Printf("     Subtasks: %d (will also be deleted)\n", len(subtasks))
+		}
+		fmt.Print("\n  Type 'yes' to confirm: ")
 
-	if confirmation != "yes" && confirmation != "y" {
-		color.Yellow("\n  ℹ️  Deletion cancelled.")
-		pause()
-		return
+		confirmation, _ := reader.ReadString('\n')
+		confirmation = strings.TrimSpace(strings.ToLower(confirmation))
+
+		if confirmation != "yes" && confirmation != "y" {
+			color.Yellow("\n  ℹ️  Deletion cancelled.")
+			pause()
+			return
+		}
 	}
 
-	deletedTask, err := manager.DeleteTask(taskID)
+	deletedTasks, err := manager.DeleteTaskTree(taskID)
 	if err != nil {
 		color.Red("\n  ❌ Failed to delete task: %v", err)
 		pause()
@@ -431,8 +601,11 @@ func deleteTaskUI(reader *bufio.Reader) {
 
 	fmt.Println()
 	color.Green("  ✅ Task deleted successfully!")
-	fmt.Printf("\n     ID: %d\n", deletedTask.ID)
-	fmt.Printf("     Title: %s\n", deletedTask.Title)
+	fmt.Printf("\n     ID: %d\n", deletedTasks[0].ID)
+	fmt.Printf("     Title: %s\n", deletedTasks[0].Title)
+	if len(deletedTasks) > 1 {
+		fmt.Printf("     Subtasks deleted: %d\n", len(deletedTasks)-1)
+	}
 	fmt.Println()
 
 	pause()
@@ -490,7 +663,7 @@ func viewStatistics() {
 	}
 
 	fmt.Println()
-	color.Cyan("  💾 Storage: %s", manager.GetStoragePath())
+	color.Cyan("  💾 Storage: %s (%s list)", manager.GetStoragePath(), activeList)
 	fmt.Println()
 
 	pause()
@@ -537,12 +710,16 @@ func exportTasksUI(reader *bufio.Reader) {
 	}
 
 	// Get all tasks
-	allTasks := manager.ListTasks(todo.FilterOptions{
+	allTasks, err := manager.ListTasks(todo.FilterOptions{
 		ShowCompleted: true,
 		ShowPending:   true,
 	})
+	if err != nil {
+		color.Red("\n  ❌ Export failed: %v", err)
+		pause()
+		return
+	}
 
-	var err error
 	switch format {
 	case "csv":
 		err = exportCSV(allTasks, filename)
@@ -573,12 +750,14 @@ func backupTasksUI() {
 	cyan.Println("╚════════════════════════════════════════════════════════════╝")
 	fmt.Println()
 
-	err := manager.BackupTasks()
+	backup, err := manager.BackupTasks()
 	if err != nil {
 		color.Red("  ❌ Backup failed: %v\n", err)
 	} else {
 		color.Green("  ✅ Backup created successfully!")
-		fmt.Printf("\n     📁 Location: %s.backup\n", manager.GetStoragePath())
+		if backup.Path != "" {
+			fmt.Printf("\n     📁 Location: %s\n", backup.Path)
+		}
 		fmt.Println()
 	}
 
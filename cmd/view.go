@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"todo-cli/internal/todo"
+	"todo-cli/storage"
+)
+
+// viewNamePattern is what a view name may look like
+var viewNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// errNoView starts the error for a view that is not saved
+var errNoView = errors.New("no view")
+
+// viewCmd represents the view command
+var viewCmd = &cobra.Command{
+	Use:   "view [name]",
+	Short: "List tasks with a saved view",
+	Long: `List tasks with a saved view: a named set of list filters.
+
+Views are saved next to the task list (views.json), so a project list has
+its own views. Any filter of 'todo list' can be saved, including --query.
+
+Examples:
+  todo view save urgent --pending --priority=high --sort=due
+  todo view save week --due-within=7d --group-by=due-bucket
+  todo view urgent                   # Same as the list command saved above
+  todo view list                     # Show saved views
+  todo view delete urgent
+  todo export --view=urgent --format=csv`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return listViews()
+		}
+
+		view, err := findView(args[0])
+		if err != nil {
+			return err
+		}
+
+		filter, err := viewFilter(*view)
+		if err != nil {
+			return fmt.Errorf("view '%s' is invalid: %w", view.Name, err)
+		}
+
+		tasks, err := manager.ListTasks(filter)
+		if err != nil {
+			return err
+		}
+		return showTaskList(tasks, filter, view.GroupBy)
+	},
+}
+
+// viewSaveCmd represents the view save command
+var viewSaveCmd = &cobra.Command{
+	Use:   "save [name]",
+	Short: "Save list filters as a view",
+	Long: `Save the given list filters under a name. Saving an existing name
+replaces that view.
+
+Examples:
+  todo view save urgent --pending --priority=high --sort=due
+  todo view save standup -q 'project:work and (due<=today or blocked)'`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := validateViewName(name); err != nil {
+			return err
+		}
+
+		view, err := flagView(cmd)
+		if err != nil {
+			return err
+		}
+		view.Name = name
+
+		// Check the filters now rather than when the view is used
+		if _, err := viewFilter(view); err != nil {
+			return err
+		}
+
+		views, err := loadViews()
+		if err != nil {
+			return err
+		}
+
+		replaced := false
+		for i := range views {
+			if views[i].Name == name {
+				views[i] = view
+				replaced = true
+			}
+		}
+		if !replaced {
+			views = append(views, view)
+		}
+
+		if err := storage.SaveViews(viewsPath(), views); err != nil {
+			return todo.StorageFailure(fmt.Errorf("failed to save view: %w", err))
+		}
+
+		if structuredOutput() {
+			return writeOutput(newViewResult(view))
+		}
+
+		if replaced {
+			fmt.Printf("🔖 View '%s' updated\n", name)
+		} else {
+			fmt.Printf("🔖 View '%s' saved\n", name)
+		}
+		if flags := viewFlags(view); flags != "" {
+			fmt.Printf("   Filters: %s\n", flags)
+		}
+		return nil
+	},
+}
+
+// viewListCmd represents the view list command
+var viewListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved views",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listViews()
+	},
+}
+
+// viewDeleteCmd represents the view delete command
+var viewDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a saved view",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		views, err := loadViews()
+		if err != nil {
+			return err
+		}
+
+		var deleted *storage.View
+		kept := make([]storage.View, 0, len(views))
+		for i := range views {
+			if views[i].Name == args[0] {
+				deleted = &views[i]
+			} else {
+				kept = append(kept, views[i])
+			}
+		}
+		if deleted == nil {
+			return fmt.Errorf("%w named '%s'", errNoView, args[0])
+		}
+
+		if err := storage.SaveViews(viewsPath(), kept); err != nil {
+			return todo.StorageFailure(fmt.Errorf("failed to delete view: %w", err))
+		}
+
+		if structuredOutput() {
+			return writeOutput(newViewResult(*deleted))
+		}
+
+		fmt.Printf("🗑️  View '%s' deleted\n", args[0])
+		return nil
+	},
+}
+
+// listViews shows the saved views and their filters
+func listViews() error {
+	views, err := loadViews()
+	if err != nil {
+		return err
+	}
+
+	if structuredOutput() {
+		return writeOutput(newViewsResult(views))
+	}
+
+	if len(views) == 0 {
+		fmt.Println("🔖 No saved views. Save one with: todo view save <name> --pending --sort=due")
+		return nil
+	}
+
+	fmt.Printf("\n🔖 Views (%d)\n", len(views))
+	fmt.Println(strings.Repeat("─", 60))
+	for _, view := range views {
+		flags := viewFlags(view)
+		if flags == "" {
+			flags = "(all tasks)"
+		}
+		fmt.Printf("%-16s %s\n", view.Name, flags)
+	}
+
+	fmt.Println()
+	return nil
+}
+
+// viewsPath returns the views file of the task list in use
+func viewsPath() string {
+	return storage.ViewsPath(taskFilePath)
+}
+
+// loadViews returns the saved views of the task list in use
+func loadViews() ([]storage.View, error) {
+	views, err := storage.LoadViews(viewsPath())
+	if err != nil {
+		return nil, todo.StorageFailure(fmt.Errorf("failed to load views: %w", err))
+	}
+	return views, nil
+}
+
+// findView returns the saved view called name
+func findView(name string) (*storage.View, error) {
+	views, err := loadViews()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(views))
+	for i := range views {
+		if views[i].Name == name {
+			return &views[i], nil
+		}
+		names = append(names, views[i].Name)
+	}
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w named '%s'. Save one with: todo view save %s [filters]", errNoView, name, name)
+	}
+	return nil, fmt.Errorf("%w named '%s'. Saved views: %s", errNoView, name, strings.Join(names, ", "))
+}
+
+// validateViewName checks that name can be used for a view
+func validateViewName(name string) error {
+	if !viewNamePattern.MatchString(name) {
+		return todo.InvalidInput(fmt.Errorf("invalid view name '%s': use letters, digits, '.', '-' and '_'", name))
+	}
+	for _, sub := range viewCmd.Commands() {
+		if sub.Name() == name {
+			return todo.InvalidInput(fmt.Errorf("'%s' is a view command and cannot be used as a view name", name))
+		}
+	}
+	return nil
+}
+
+// viewFlags returns the list flags a view stands for
+func viewFlags(view storage.View) string {
+	var flags []string
+	if view.Completed {
+		flags = append(flags, "--completed")
+	}
+	if view.Pending {
+		flags = append(flags, "--pending")
+	}
+	if view.Priority != "" {
+		flags = append(flags, "--priority="+string(view.Priority))
+	}
+	if view.Search != "" {
+		flags = append(flags, "--search="+strconv.Quote(view.Search))
+	}
+	if view.SearchRegex {
+		flags = append(flags, "--regex")
+	}
+	if view.Project != "" {
+		flags = append(flags, "--project="+view.Project)
+	}
+	for _, tag := range view.Tags {
+		flags = append(flags, "--tag="+tag)
+	}
+	for _, tag := range view.ExcludeTags {
+		flags = append(flags, "--exclude-tag="+tag)
+	}
+	if view.Blocked != nil {
+		if *view.Blocked {
+			flags = append(flags, "--blocked")
+		} else {
+			flags = append(flags, "--unblocked")
+		}
+	}
+	if view.Query != "" {
+		flags = append(flags, "--query="+strconv.Quote(view.Query))
+	}
+
+	dates := []struct{ flag, value string }{
+		{"due-before", view.DueBefore},
+		{"due-after", view.DueAfter},
+		{"due-within", view.DueWithin},
+		{"created-before", view.CreatedBefore},
+		{"created-after", view.CreatedAfter},
+		{"created-since", view.CreatedSince},
+		{"completed-before", view.CompletedBefore},
+		{"completed-after", view.CompletedAfter},
+		{"completed-since", view.CompletedSince},
+	}
+	for _, date := range dates {
+		if date.value != "" {
+			flags = append(flags, "--"+date.flag+"="+quoteIfSpaced(date.value))
+		}
+	}
+	if view.Overdue {
+		flags = append(flags, "--overdue")
+	}
+	if view.NoDue {
+		flags = append(flags, "--no-due")
+	}
+
+	if view.Sort != "" {
+		flags = append(flags, "--sort="+view.Sort)
+	}
+	if view.GroupBy != "" {
+		flags = append(flags, "--group-by="+view.GroupBy)
+	}
+	return strings.Join(flags, " ")
+}
+
+// quoteIfSpaced quotes a flag value that contains spaces
+func quoteIfSpaced(value string) string {
+	if strings.ContainsAny(value, " \t") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+func init() {
+	rootCmd.AddCommand(viewCmd)
+	viewCmd.AddCommand(viewSaveCmd)
+	viewCmd.AddCommand(viewListCmd)
+	viewCmd.AddCommand(viewDeleteCmd)
+
+	// Add flags
+	addFilterFlags(viewSaveCmd)
+	viewCmd.Flags().BoolVar(&listFlat, "flat", false, "Show subtasks as a flat list instead of a tree")
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"todo-cli/internal/todo"
+	"todo-cli/storage"
+)
+
+// resetFlags puts the flags of cmd back to their defaults, since they keep
+// their values from one Execute to the next
+func resetFlags(t *testing.T, cmd *cobra.Command) {
+	t.Helper()
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		var err error
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			err = slice.Replace(nil)
+		} else {
+			err = f.Value.Set(f.DefValue)
+		}
+		if err != nil {
+			t.Fatalf("resetting --%s: %v", f.Name, err)
+		}
+		f.Changed = false
+	})
+}
+
+func TestViewSaveRoundTrip(t *testing.T) {
+	blocked, unblocked := true, false
+
+	tests := []struct {
+		name  string
+		args  []string
+		want  storage.View
+		flags string // as shown by view list
+	}{
+		{
+			name: "filters",
+			args: []string{"--pending", "--priority=high", "--search=deploy.*release", "--regex", "--project=work.api",
+				"--tag=ops", "--tag=urgent", "--exclude-tag=later", "--blocked", "--query=priority:high", "--sort=-priority,due", "--group-by=project"},
+			want: storage.View{Pending: true, Priority: storage.PriorityHigh, Search: "deploy.*release", SearchRegex: true,
+				Project: "work.api", Tags: []string{"ops", "urgent"}, ExcludeTags: []string{"later"}, Blocked: &blocked,
+				Query: "priority:high", Sort: "-priority,due", GroupBy: "project"},
+			flags: `--pending --priority=high --search="deploy.*release" --regex --project=work.api --tag=ops --tag=urgent ` +
+				`--exclude-tag=later --blocked --query="priority:high" --sort=-priority,due --group-by=project`,
+		},
+		{
+			name: "date ranges",
+			args: []string{"--completed", "--unblocked", "--due-before=2026-03-10", "--due-after=2026-03-01", "--overdue",
+				"--created-before=2026-02-01", "--created-after=2026-01-01", "--completed-before=2026-03-05", "--completed-since=2026-03-01"},
+			want: storage.View{Completed: true, Blocked: &unblocked, DueBefore: "2026-03-10", DueAfter: "2026-03-01", Overdue: true,
+				CreatedBefore: "2026-02-01", CreatedAfter: "2026-01-01", CompletedBefore: "2026-03-05", CompletedSince: "2026-03-01"},
+			flags: "--completed --unblocked --due-before=2026-03-10 --due-after=2026-03-01 --created-before=2026-02-01 " +
+				"--created-after=2026-01-01 --completed-before=2026-03-05 --completed-since=2026-03-01 --overdue",
+		},
+		{
+			name:  "relative dates",
+			args:  []string{"--due-within=7d", "--created-since=-7d", "--completed-after=next monday"},
+			want:  storage.View{DueWithin: "7d", CreatedSince: "-7d", CompletedAfter: "next monday"},
+			flags: `--due-within=7d --created-since=-7d --completed-after="next monday"`,
+		},
+		{
+			name:  "no due date",
+			args:  []string{"--no-due"},
+			want:  storage.View{NoDue: true},
+			flags: "--no-due",
+		},
+	}
+
+	// Every filter option is set by one of the views
+	set := make(map[string]bool)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			list := filepath.Join(dir, "tasks.json")
+
+			resetFlags(t, viewSaveCmd)
+			defer resetFlags(t, viewSaveCmd)
+			args := append([]string{"--file", list, "view", "save", "saved"}, tt.args...)
+			if err := runCommand(t, dir, args...); err != nil {
+				t.Fatalf("view save: %v", err)
+			}
+
+			views, err := storage.LoadViews(storage.ViewsPath(list))
+			if err != nil {
+				t.Fatalf("LoadViews() error = %v", err)
+			}
+			want := tt.want
+			want.Name = "saved"
+			if len(views) != 1 || !reflect.DeepEqual(views[0], want) {
+				t.Fatalf("loaded views = %+v, want %+v", views, want)
+			}
+
+			if got := viewFlags(views[0]); got != tt.flags {
+				t.Errorf("viewFlags() = %s\nwant %s", got, tt.flags)
+			}
+
+			filter, err := viewFilter(views[0])
+			if err != nil {
+				t.Fatalf("viewFilter() error = %v", err)
+			}
+			value := reflect.ValueOf(filter)
+			for i := 0; i < value.NumField(); i++ {
+				if !value.Field(i).IsZero() {
+					set[value.Type().Field(i).Name] = true
+				}
+			}
+		})
+	}
+
+	fields := reflect.TypeOf(todo.FilterOptions{})
+	for i := 0; i < fields.NumField(); i++ {
+		if name := fields.Field(i).Name; !set[name] {
+			t.Errorf("no view sets FilterOptions.%s", name)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"todo-cli/internal/todo"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix is the prefix of environment variables that override the file
+const envPrefix = "TODO_"
+
+// Config holds user settings loaded from the config file and environment
+type Config struct {
+	StoragePath     string        `yaml:"storage_path"`
+	Backend         string        `yaml:"backend"`
+	DefaultPriority string        `yaml:"default_priority"`
+	DefaultSort     string        `yaml:"default_sort"`
+	DateFormat      string        `yaml:"date_format"`
+	Timezone        string        `yaml:"timezone"` // IANA name such as Europe/Berlin; empty for the system zone
+	Color           bool          `yaml:"color"`
+	Confirm         bool          `yaml:"confirm"`
+	LockTimeout     time.Duration `yaml:"lock_timeout"`
+	BackupRetention int           `yaml:"backup_retention"`
+	AutoBackup      bool          `yaml:"auto_backup"`
+}
+
+// Default returns the settings used when nothing is configured
+func Default() *Config {
+	return &Config{
+		Backend:         "json",
+		DefaultPriority: "medium",
+		DefaultSort:     "id",
+		DateFormat:      "2006-01-02",
+		Color:           true,
+		Confirm:         true,
+		LockTimeout:     5 * time.Second,
+		BackupRetention: 10,
+	}
+}
+
+// DefaultPath returns the default config file location, $HOME/.todo/config.yaml
+func DefaultPath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "config.yaml"
+	}
+	return filepath.Join(homeDir, ".todo", "config.yaml")
+}
+
+// Load reads settings from path (or DefaultPath if empty) and applies TODO_*
+// environment overrides, such as TODO_STORAGE_PATH or TODO_COLOR=false. A
+// missing default config file is not an error; a missing explicit one is.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	case errors.Is(err, os.ErrNotExist) && !explicit:
+		// No config file, use defaults
+	default:
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	cfg.StoragePath = expandHome(cfg.StoragePath)
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides settings from TODO_<KEY> environment variables, where
+// KEY is the upper-cased YAML key
+func (c *Config) applyEnv() error {
+	stringFields := map[string]*string{
+		"storage_path":     &c.StoragePath,
+		"backend":          &c.Backend,
+		"default_priority": &c.DefaultPriority,
+		"default_sort":     &c.DefaultSort,
+		"date_format":      &c.DateFormat,
+		"timezone":         &c.Timezone,
+	}
+	for key, field := range stringFields {
+		if value, ok := lookupEnv(key); ok {
+			*field = value
+		}
+	}
+
+	boolFields := map[string]*bool{
+		"color":       &c.Color,
+		"confirm":     &c.Confirm,
+		"auto_backup": &c.AutoBackup,
+	}
+	for key, field := range boolFields {
+		if value, ok := lookupEnv(key); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s%s: %q is not a boolean", envPrefix, envName(key), value)
+			}
+			*field = parsed
+		}
+	}
+
+	if value, ok := lookupEnv("lock_timeout"); ok {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %q is not a duration", envPrefix, envName("lock_timeout"), value)
+		}
+		c.LockTimeout = parsed
+	}
+
+	if value, ok := lookupEnv("backup_retention"); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %q is not a number", envPrefix, envName("backup_retention"), value)
+		}
+		c.BackupRetention = parsed
+	}
+
+	return nil
+}
+
+// Validate checks that the settings have usable values
+func (c *Config) Validate() error {
+	switch c.Backend {
+	case "json", "sqlite":
+	default:
+		return fmt.Errorf("backend must be json or sqlite, got %q", c.Backend)
+	}
+
+	switch c.DefaultPriority {
+	case "low", "medium", "high":
+	default:
+		return fmt.Errorf("default_priority must be low, medium or high, got %q", c.DefaultPriority)
+	}
+
+	if _, err := todo.ParseSort(c.DefaultSort); err != nil {
+		return fmt.Errorf("default_sort %q is not a valid sort order: %w", c.DefaultSort, err)
+	}
+
+	if c.DateFormat == "" {
+		return fmt.Errorf("date_format cannot be empty")
+	}
+
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("timezone %q is not a known time zone", c.Timezone)
+		}
+	}
+
+	if c.LockTimeout < 0 {
+		return fmt.Errorf("lock_timeout cannot be negative")
+	}
+	return nil
+}
+
+// Location returns the configured time zone, or the system time zone. The
+// system zone is looked up by name where possible so that it can be recorded
+// with due dates.
+func (c *Config) Location() *time.Location {
+	if c.Timezone != "" {
+		if loc, err := time.LoadLocation(c.Timezone); err == nil {
+			return loc
+		}
+	}
+	return localZone()
+}
+
+// localZone returns the system time zone, named after $TZ or the
+// /etc/localtime link when they name a known zone
+func localZone() *time.Location {
+	name := strings.TrimPrefix(os.Getenv("TZ"), ":")
+	if name == "" {
+		if target, err := os.Readlink("/etc/localtime"); err == nil {
+			if _, zone, ok := strings.Cut(target, "zoneinfo/"); ok {
+				name = zone
+			}
+		}
+	}
+
+	if name != "" {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.Local
+}
+
+// lookupEnv returns the environment override for a YAML key
+func lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(envPrefix + envName(key))
+}
+
+// envName converts a YAML key to its environment variable suffix
+func envName(key string) string {
+	return strings.ToUpper(key)
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,28 @@
+package config
+
+import "testing"
+
+func TestValidateDefaultSort(t *testing.T) {
+	tests := []struct {
+		sort    string
+		wantErr bool
+	}{
+		{"id", false},
+		{"-priority,due,id", false},
+		{"relevance", false},
+		{"+title, -created", false},
+		{"", false},
+		{"size", true},
+		{"due,due", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			c := Default()
+			c.DefaultSort = tt.sort
+			if err := c.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() with default_sort %q error = %v, wantErr %v", tt.sort, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+// Package dateparse reads dates the way people type them, such as "tomorrow",
+// "fri 5pm", "next monday", "in 3 days", "2w" or "eom", as well as ISO dates.
+// Relative dates are resolved against a clock, so results are reproducible.
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// isoLayouts are the absolute formats always accepted
+var isoLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+// units maps the words and one-letter suffixes of offsets such as "in 3
+// weeks" or "2w" to their unit
+var units = map[string]byte{
+	"d": 'd', "day": 'd', "days": 'd',
+	"w": 'w', "week": 'w', "weeks": 'w',
+	"m": 'm', "month": 'm', "months": 'm',
+	"y": 'y', "year": 'y', "years": 'y',
+}
+
+var (
+	shortOffset = regexp.MustCompile(`^([+-]?)(\d+)([dwmy])$`)
+	clockTime   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+)
+
+// Parser resolves dates relative to the current time in a time zone
+type Parser struct {
+	Now      func() time.Time // clock; time.Now if nil
+	Location *time.Location   // time zone of the results; time.Local if nil
+	Layouts  []string         // extra absolute formats, tried after the ISO ones
+}
+
+// New creates a parser using the system clock and the local time zone
+func New() *Parser {
+	return &Parser{Now: time.Now, Location: time.Local}
+}
+
+// Parse reads a date and reports whether it included a time of day. Dates
+// without one are returned at midnight. Accepted forms:
+//
+//	2025-10-20, 2025-10-20 17:30
+//	today, tomorrow, yesterday
+//	fri, friday       the next Friday after today
+//	this fri          Friday this week, or today if it is Friday
+//	next fri          Friday next week (weeks start on Monday)
+//	next week         Monday next week; also next month, next year
+//	in 3 days, in a week, 2w, 3d, 1m, 1y
+//	3 days ago, a week ago, -3d, -2w
+//	eod, eow, eom, eoy   end of the day, week (Sunday), month or year
+//	oct 20, 20 oct    the next October 20th
+//	5pm, 5:30pm, 17:00, noon, midnight, alone or after a date ("fri at 5pm")
+//
+// A time alone means today, or tomorrow if that time has already passed.
+func (p *Parser) Parse(text string) (time.Time, bool, error) {
+	loc := p.location()
+	now := p.now().In(loc)
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return time.Time{}, false, fmt.Errorf("empty date")
+	}
+
+	for _, layout := range append(isoLayouts, p.Layouts...) {
+		if t, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return t, strings.Contains(layout, "04"), nil
+		}
+	}
+
+	today := midnight(now)
+	date, hasDate := today, false
+	hour, minute, hasTime := 0, 0, false
+
+	words := strings.Fields(strings.ToLower(text))
+	for len(words) > 0 {
+		if !hasDate {
+			if d, n := parseDay(words, today); n > 0 {
+				date, hasDate = d, true
+				words = words[n:]
+				continue
+			}
+		}
+		if !hasTime {
+			if h, m, n := parseClock(words); n > 0 {
+				hour, minute, hasTime = h, m, true
+				words = words[n:]
+				continue
+			}
+		}
+		return time.Time{}, false, fmt.Errorf("unrecognized date %q. Use YYYY-MM-DD, YYYY-MM-DD HH:MM or words such as today, fri, in 3 days, eom", text)
+	}
+
+	if !hasTime {
+		return date, false, nil
+	}
+
+	result := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc)
+	if !hasDate && result.Before(now) {
+		result = result.AddDate(0, 0, 1)
+	}
+	return result, true, nil
+}
+
+// now returns the current time from the parser's clock
+func (p *Parser) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+	return p.Now()
+}
+
+// location returns the parser's time zone
+func (p *Parser) location() *time.Location {
+	if p.Location == nil {
+		return time.Local
+	}
+	return p.Location
+}
+
+// parseDay reads a date expression at the start of words and returns the
+// date and the number of words used, or zero if words do not start with one
+func parseDay(words []string, today time.Time) (time.Time, int) {
+	switch word := words[0]; word {
+	case "today", "tod", "eod":
+		return today, 1
+	case "tomorrow", "tom", "tmr", "tmrw":
+		return today.AddDate(0, 0, 1), 1
+	case "yesterday":
+		return today.AddDate(0, 0, -1), 1
+	case "eow":
+		return weekStart(today).AddDate(0, 0, 6), 1
+	case "eom":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()), 1
+	case "eoy":
+		return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()), 1
+
+	case "this":
+		if len(words) > 1 {
+			if day, ok := weekdays[words[1]]; ok {
+				return today.AddDate(0, 0, daysUntil(today, day)), 2
+			}
+		}
+		return time.Time{}, 0
+
+	case "next":
+		if len(words) < 2 {
+			return time.Time{}, 0
+		}
+		nextWeek := weekStart(today).AddDate(0, 0, 7)
+		if day, ok := weekdays[words[1]]; ok {
+			return nextWeek.AddDate(0, 0, (int(day)+6)%7), 2
+		}
+		switch words[1] {
+		case "week":
+			return nextWeek, 2
+		case "month":
+			return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), 2
+		case "year":
+			return time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location()), 2
+		}
+		return time.Time{}, 0
+
+	case "in":
+		if len(words) < 3 {
+			return time.Time{}, 0
+		}
+		count, err := strconv.Atoi(words[1])
+		if words[1] == "a" || words[1] == "an" {
+			count, err = 1, nil
+		}
+		unit, ok := units[words[2]]
+		if err != nil || !ok || len(words[2]) == 1 {
+			return time.Time{}, 0
+		}
+		return addOffset(today, count, unit), 3
+	}
+
+	if day, ok := weekdays[words[0]]; ok {
+		days := daysUntil(today, day)
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), 1
+	}
+
+	if match := shortOffset.FindStringSubmatch(words[0]); match != nil {
+		count, _ := strconv.Atoi(match[2])
+		if match[1] == "-" {
+			count = -count
+		}
+		return addOffset(today, count, match[3][0]), 1
+	}
+
+	// "3 days ago", "a week ago"
+	if len(words) > 2 && words[2] == "ago" {
+		count, err := strconv.Atoi(words[0])
+		if words[0] == "a" || words[0] == "an" {
+			count, err = 1, nil
+		}
+		if unit, ok := units[words[1]]; ok && err == nil && len(words[1]) > 1 {
+			return addOffset(today, -count, unit), 3
+		}
+	}
+
+	// "oct 20" or "20 oct"
+	if len(words) > 1 {
+		month, ok := months[words[0]]
+		dayText := words[1]
+		if !ok {
+			month, ok = months[words[1]]
+			dayText = words[0]
+		}
+		if day, err := strconv.Atoi(dayText); ok && err == nil && day >= 1 && day <= daysIn(today.Year(), month) {
+			date := time.Date(today.Year(), month, day, 0, 0, 0, 0, today.Location())
+			if date.Before(today) {
+				date = time.Date(today.Year()+1, month, min(day, daysIn(today.Year()+1, month)), 0, 0, 0, 0, today.Location())
+			}
+			return date, 2
+		}
+	}
+
+	return time.Time{}, 0
+}
+
+// parseClock reads a time of day at the start of words, optionally after
+// "at", and returns the hour, minute and number of words used, or zero words
+// if words do not start with a time
+func parseClock(words []string) (hour, minute, n int) {
+	if words[0] == "at" {
+		if len(words) == 1 {
+			return 0, 0, 0
+		}
+		hour, minute, n = parseClock(words[1:])
+		if n == 0 {
+			// A bare hour is only a time after "at": "fri at 5"
+			if h, err := strconv.Atoi(words[1]); err == nil && h >= 0 && h <= 23 {
+				return h, 0, 2
+			}
+			return 0, 0, 0
+		}
+		return hour, minute, n + 1
+	}
+
+	switch words[0] {
+	case "noon":
+		return 12, 0, 1
+	case "midnight":
+		return 0, 0, 1
+	}
+
+	match := clockTime.FindStringSubmatch(words[0])
+	if match == nil {
+		return 0, 0, 0
+	}
+	n = 1
+
+	meridiem := match[3]
+	if meridiem == "" && len(words) > 1 && (words[1] == "am" || words[1] == "pm") {
+		meridiem = words[1]
+		n = 2
+	}
+	if meridiem == "" && match[2] == "" {
+		// A bare number such as "5" is not a time
+		return 0, 0, 0
+	}
+
+	hour, _ = strconv.Atoi(match[1])
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+	if minute > 59 {
+		return 0, 0, 0
+	}
+
+	switch meridiem {
+	case "":
+		if hour > 23 {
+			return 0, 0, 0
+		}
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, 0
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	return hour, minute, n
+}
+
+// addOffset adds count days, weeks, months or years to date. Adding months
+// or years keeps the day within the target month, so Jan 31 + 1 month is the
+// last day of February.
+func addOffset(date time.Time, count int, unit byte) time.Time {
+	switch unit {
+	case 'd':
+		return date.AddDate(0, 0, count)
+	case 'w':
+		return date.AddDate(0, 0, 7*count)
+	case 'y':
+		count *= 12
+	}
+
+	first := time.Date(date.Year(), date.Month()+time.Month(count), 1, 0, 0, 0, 0, date.Location())
+	day := min(date.Day(), daysIn(first.Year(), first.Month()))
+	return first.AddDate(0, 0, day-1)
+}
+
+// midnight returns the start of the day of t
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// weekStart returns the Monday of the week of date
+func weekStart(date time.Time) time.Time {
+	return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+}
+
+// daysUntil returns how many days from date until the next day (0 if date
+// is that day)
+func daysUntil(date time.Time, day time.Weekday) int {
+	return (int(day) - int(date.Weekday()) + 7) % 7
+}
+
+// daysIn returns the number of days in a month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
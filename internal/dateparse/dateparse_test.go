@@ -0,0 +1,68 @@
+package dateparse
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the zones below, even where the system has no zone database
+)
+
+func TestParseAcrossDST(t *testing.T) {
+	const layout = "2006-01-02 15:04 MST"
+
+	tests := []struct {
+		zone     string
+		now      string // in zone
+		text     string
+		want     string
+		wantTime bool
+	}{
+		// Berlin moves to summer time at 02:00 on 2026-03-29
+		{"Europe/Berlin", "2026-03-28 10:00", "tomorrow", "2026-03-29 00:00 CET", false},
+		{"Europe/Berlin", "2026-03-28 10:00", "tomorrow 9am", "2026-03-29 09:00 CEST", true},
+		{"Europe/Berlin", "2026-03-28 10:00", "in 2 days", "2026-03-30 00:00 CEST", false},
+		{"Europe/Berlin", "2026-03-28 10:00", "1w", "2026-04-04 00:00 CEST", false},
+		{"Europe/Berlin", "2026-03-28 10:00", "next monday", "2026-03-30 00:00 CEST", false},
+		{"Europe/Berlin", "2026-03-31 10:00", "3 days ago", "2026-03-28 00:00 CET", false},
+
+		// and back to standard time at 03:00 on 2026-10-25
+		{"Europe/Berlin", "2026-10-24 22:00", "tomorrow", "2026-10-25 00:00 CEST", false},
+		{"Europe/Berlin", "2026-10-24 22:00", "tomorrow 3pm", "2026-10-25 15:00 CET", true},
+		{"Europe/Berlin", "2026-10-24 22:00", "8am", "2026-10-25 08:00 CET", true},
+		{"Europe/Berlin", "2026-10-24 22:00", "eow", "2026-10-25 00:00 CEST", false},
+		{"Europe/Berlin", "2026-10-24 22:00", "2d", "2026-10-26 00:00 CET", false},
+
+		// New York moves to daylight time at 02:00 on 2026-03-08
+		{"America/New_York", "2026-03-07 23:30", "tomorrow", "2026-03-08 00:00 EST", false},
+		{"America/New_York", "2026-03-07 23:30", "1d 5pm", "2026-03-08 17:00 EDT", true},
+		{"America/New_York", "2026-03-07 23:30", "11pm", "2026-03-08 23:00 EDT", true},
+		{"America/New_York", "2026-03-07 23:30", "1m", "2026-04-07 00:00 EDT", false},
+
+		// and back to standard time at 02:00 on 2026-11-01
+		{"America/New_York", "2026-10-31 12:00", "tomorrow", "2026-11-01 00:00 EDT", false},
+		{"America/New_York", "2026-10-31 12:00", "tomorrow noon", "2026-11-01 12:00 EST", true},
+		{"America/New_York", "2026-10-31 12:00", "in a week", "2026-11-07 00:00 EST", false},
+		{"America/New_York", "2026-11-02 09:00", "-2d", "2026-10-31 00:00 EDT", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zone+" "+tt.now+" "+tt.text, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			now, err := time.ParseInLocation("2006-01-02 15:04", tt.now, loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			p := &Parser{Now: func() time.Time { return now }, Location: loc}
+			got, hasTime, err := p.Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.text, err)
+			}
+			if got.Format(layout) != tt.want || hasTime != tt.wantTime {
+				t.Errorf("Parse(%q) = %s, %v, want %s, %v", tt.text, got.Format(layout), hasTime, tt.want, tt.wantTime)
+			}
+		})
+	}
+}
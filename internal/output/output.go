@@ -0,0 +1,100 @@
+// Package output writes command results for scripts as JSON, YAML or
+// tab-separated values. Field names are part of the interface: they do not
+// change when the wording of the text output does.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format
+type Format string
+
+const (
+	Text Format = "text" // human-readable text, written by each command itself
+	JSON Format = "json"
+	YAML Format = "yaml"
+	TSV  Format = "tsv"
+)
+
+// FormatNames lists the output formats, for help and error messages
+const FormatNames = "text, json, yaml, tsv"
+
+// ParseFormat returns the format called name
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case Text, JSON, YAML, TSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid output format '%s'. Valid formats: %s", name, FormatNames)
+}
+
+// Table is a result that can be written as tab-separated values: a header
+// line of column names followed by one line per row
+type Table interface {
+	Columns() []string
+	Rows() [][]string
+}
+
+// Write writes v to w in format f. JSON and YAML encode v as it is, using
+// its json and yaml field tags; TSV needs v to be a Table.
+func Write(w io.Writer, f Format, v any) error {
+	switch f {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(v)
+	case YAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	case TSV:
+		table, ok := v.(Table)
+		if !ok {
+			return fmt.Errorf("%T cannot be written as tsv", v)
+		}
+		return writeTSV(w, table)
+	}
+	return fmt.Errorf("cannot write %s output", f)
+}
+
+// writeTSV writes a table as tab-separated values
+func writeTSV(w io.Writer, table Table) error {
+	if err := writeTSVLine(w, table.Columns()); err != nil {
+		return err
+	}
+	for _, row := range table.Rows() {
+		if err := writeTSVLine(w, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTSVLine writes one line of tab-separated values
+func writeTSVLine(w io.Writer, fields []string) error {
+	escaped := make([]string, len(fields))
+	for i, field := range fields {
+		escaped[i] = tsvEscaper.Replace(field)
+	}
+	_, err := fmt.Fprintln(w, strings.Join(escaped, "\t"))
+	return err
+}
+
+// tsvEscaper escapes the characters that would break a line of
+// tab-separated values, so every record stays on one line
+var tsvEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"\t", `\t`,
+	"\n", `\n`,
+	"\r", `\r`,
+)
@@ -0,0 +1,80 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+// table is a Table for tests
+type table struct {
+	columns []string
+	rows    [][]string
+}
+
+func (t table) Columns() []string { return t.columns }
+func (t table) Rows() [][]string  { return t.rows }
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{"text", Text, false},
+		{"JSON", JSON, false},
+		{"yaml", YAML, false},
+		{"Tsv", TSV, false},
+		{"csv", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.name)
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Errorf("ParseFormat(%q) = %q, %v, want %q, error %v", tt.name, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	result := table{
+		columns: []string{"id", "title"},
+		rows: [][]string{
+			{"1", "plain"},
+			{"2", "tab\there, line\nbreak\r"},
+			{"3", `back\slash`},
+		},
+	}
+
+	tests := []struct {
+		format Format
+		value  any
+		want   string
+	}{
+		{TSV, result, "id\ttitle\n1\tplain\n2\ttab\\there, line\\nbreak\\r\n3\tback\\\\slash\n"},
+		{TSV, table{columns: []string{"id"}}, "id\n"},
+		{JSON, map[string]string{"title": "<b> & </b>"}, "{\n  \"title\": \"<b> & </b>\"\n}\n"},
+		{YAML, map[string][]int{"ids": {1, 2}}, "ids:\n  - 1\n  - 2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var b strings.Builder
+			if err := Write(&b, tt.format, tt.value); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Write() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteTSVNeedsTable(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, TSV, map[string]string{}); err == nil {
+		t.Error("Write() of a map as tsv succeeded, want an error")
+	}
+}
@@ -0,0 +1,224 @@
+package query
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"todo-cli/internal/todo"
+)
+
+// fieldNames lists the fields for error messages
+const fieldNames = "priority, tag, project, title, id, parent, is, due, created, completed"
+
+var priorityRanks = map[todo.Priority]int{
+	todo.PriorityLow:    1,
+	todo.PriorityMedium: 2,
+	todo.PriorityHigh:   3,
+}
+
+// states are the conditions that can be written as a bare word or with is:
+var states = map[string]matcher{
+	"done":      func(task *todo.Task, env Env) bool { return task.Completed },
+	"completed": func(task *todo.Task, env Env) bool { return task.Completed },
+	"pending":   func(task *todo.Task, env Env) bool { return !task.Completed },
+	"overdue":   func(task *todo.Task, env Env) bool { return env.IsOverdue(task) },
+	"blocked":   func(task *todo.Task, env Env) bool { return env.IsBlocked(task) },
+	"recurring": func(task *todo.Task, env Env) bool { return task.IsRecurring() },
+	"subtask":   func(task *todo.Task, env Env) bool { return task.ParentID != 0 },
+}
+
+// word matches a state, or tasks whose title contains text
+func word(text string) matcher {
+	if state, ok := states[strings.ToLower(text)]; ok {
+		return state
+	}
+	return titleContains(text)
+}
+
+// titleContains matches tasks whose title contains text, ignoring case
+func titleContains(text string) matcher {
+	text = strings.ToLower(text)
+	return func(task *todo.Task, env Env) bool {
+		return strings.Contains(strings.ToLower(task.Title), text)
+	}
+}
+
+// compare applies a comparison operator to the result of a Compare-style
+// function (negative, zero or positive)
+func compare(c int, op string) bool {
+	switch op {
+	case ":", "=":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// isEquality reports whether op only tests for (in)equality
+func isEquality(op string) bool {
+	return op == ":" || op == "=" || op == "!="
+}
+
+// comparison compiles a field comparison such as priority>=medium
+func (ps *parser) comparison(field, op, value token) (matcher, error) {
+	name := strings.ToLower(field.text)
+	text := value.text
+	negate := op.text == "!="
+
+	// Fields that only support equality
+	switch name {
+	case "tag", "tags", "project", "proj", "is", "status":
+		if !isEquality(op.text) {
+			return nil, ps.errorAt(op, "operator '%s' cannot be used with %s", op.text, field.text)
+		}
+	}
+
+	var match matcher
+	switch name {
+	case "priority", "pri":
+		rank, ok := priorityRanks[todo.Priority(strings.ToLower(text))]
+		if !ok {
+			return nil, ps.errorAt(value, "unknown priority %s (valid: low, medium, high)", value.describe())
+		}
+		return func(task *todo.Task, env Env) bool {
+			return compare(priorityRanks[task.Priority]-rank, op.text)
+		}, nil
+
+	case "tag", "tags":
+		tag := todo.NormalizeTag(text)
+		if tag == "" {
+			return nil, ps.errorAt(value, "invalid tag %s", value.describe())
+		}
+		match = func(task *todo.Task, env Env) bool { return task.HasTag(tag) }
+
+	case "project", "proj":
+		project, err := todo.NormalizeProject(text)
+		if err != nil || project == "" {
+			return nil, ps.errorAt(value, "invalid project %s", value.describe())
+		}
+		match = func(task *todo.Task, env Env) bool { return task.InProject(project) }
+
+	case "is", "status":
+		state, ok := states[strings.ToLower(text)]
+		if !ok {
+			return nil, ps.errorAt(value, "unknown state %s (valid: %s)", value.describe(), stateNames())
+		}
+		match = state
+
+	case "title", "text":
+		switch op.text {
+		case ":":
+			match = titleContains(text)
+		case "=", "!=":
+			match = func(task *todo.Task, env Env) bool { return strings.EqualFold(task.Title, text) }
+		default:
+			return nil, ps.errorAt(op, "operator '%s' cannot be used with %s", op.text, field.text)
+		}
+
+	case "id", "parent":
+		n, err := strconv.Atoi(text)
+		if name == "parent" && strings.EqualFold(text, "none") {
+			n, err = 0, nil
+		}
+		if err != nil {
+			return nil, ps.errorAt(value, "%s must be a number, got %s", field.text, value.describe())
+		}
+		get := func(task *todo.Task) int { return task.ID }
+		if name == "parent" {
+			get = func(task *todo.Task) int { return task.ParentID }
+		}
+		return func(task *todo.Task, env Env) bool {
+			return compare(get(task)-n, op.text)
+		}, nil
+
+	case "due":
+		return ps.dateComparison(field, op, value, func(task *todo.Task, env Env) (time.Time, bool) {
+			if task.DueDate == nil {
+				return time.Time{}, false
+			}
+			return task.DueIn(env.Location()), true
+		})
+
+	case "created":
+		return ps.dateComparison(field, op, value, func(task *todo.Task, env Env) (time.Time, bool) {
+			return task.CreatedAt, true
+		})
+
+	case "completed":
+		return ps.dateComparison(field, op, value, func(task *todo.Task, env Env) (time.Time, bool) {
+			if task.CompletedAt == nil {
+				return time.Time{}, false
+			}
+			return *task.CompletedAt, true
+		})
+
+	default:
+		return nil, ps.errorAt(field, "unknown field '%s' (fields: %s)", field.text, fieldNames)
+	}
+
+	if negate {
+		return not(match), nil
+	}
+	return match, nil
+}
+
+// dateComparison compiles a comparison of a date field. A value without a
+// time of day compares calendar days in the query's time zone; "none"
+// matches tasks without the date. Tasks without the date never match other
+// comparisons.
+func (ps *parser) dateComparison(field, op, value token, get func(task *todo.Task, env Env) (time.Time, bool)) (matcher, error) {
+	if strings.EqualFold(value.text, "none") {
+		if !isEquality(op.text) {
+			return nil, ps.errorAt(op, "operator '%s' cannot be used with none", op.text)
+		}
+		wantNone := op.text != "!="
+		return func(task *todo.Task, env Env) bool {
+			_, ok := get(task, env)
+			return ok != wantNone
+		}, nil
+	}
+
+	target, hasTime, err := ps.dates.Parse(value.text)
+	if err != nil {
+		return nil, ps.errorAt(value, "invalid date %s for %s", value.describe(), field.text)
+	}
+
+	return func(task *todo.Task, env Env) bool {
+		at, ok := get(task, env)
+		if !ok {
+			return false
+		}
+		if hasTime {
+			return compare(at.Compare(target), op.text)
+		}
+		loc := env.Location()
+		return compare(startOfDay(at, loc).Compare(startOfDay(target, loc)), op.text)
+	}, nil
+}
+
+// startOfDay returns the start of the day of t in loc
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	y, m, d := t.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// stateNames lists the states for error messages
+func stateNames() string {
+	names := make([]string, 0, len(states))
+	for name := range states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,101 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind identifies the kind of a token
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokWord             // a bare word: field name, keyword or value
+	tokString           // a double-quoted value
+	tokOp               // a comparison operator: : = != < <= > >=
+	tokLParen
+	tokRParen
+)
+
+// token is a lexical token and its byte offset in the query
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// describe returns the token as shown in error messages
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return `"` + t.text + `"`
+	}
+	return "'" + t.text + "'"
+}
+
+// isWordRune reports whether r can be part of a bare word
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`():=<>!"`, r)
+}
+
+// lex splits a query into tokens, ending with a tokEOF token
+func lex(text string) ([]token, error) {
+	var tokens []token
+	pos := 0
+
+	for pos < len(text) {
+		r, size := utf8.DecodeRuneInString(text[pos:])
+		start := pos
+
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: start})
+			pos++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: start})
+			pos++
+
+		case r == ':' || r == '=':
+			tokens = append(tokens, token{kind: tokOp, text: string(r), pos: start})
+			pos++
+
+		case r == '<' || r == '>' || r == '!':
+			op := string(r)
+			if strings.HasPrefix(text[pos+1:], "=") {
+				op += "="
+			}
+			if op == "!" {
+				return nil, syntaxError(text, start, "unexpected '!'; use 'not' to negate, or != to compare")
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: start})
+			pos += len(op)
+
+		case r == '"':
+			end := strings.IndexByte(text[pos+1:], '"')
+			if end < 0 {
+				return nil, syntaxError(text, start, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokString, text: text[pos+1 : pos+1+end], pos: start})
+			pos += end + 2
+
+		default:
+			for pos < len(text) {
+				r, size := utf8.DecodeRuneInString(text[pos:])
+				if !isWordRune(r) {
+					break
+				}
+				pos += size
+			}
+			tokens = append(tokens, token{kind: tokWord, text: text[start:pos], pos: start})
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(text)}), nil
+}
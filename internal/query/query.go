@@ -0,0 +1,279 @@
+// Package query parses and evaluates task filter expressions such as
+//
+//	priority:high and (tag:ops or due<7d) and not done
+//
+// Conditions are combined with and, or and not, grouped with parentheses,
+// and written side by side they are joined with and. See Parse for the
+// fields and values that can be used.
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/todo"
+)
+
+// Env provides what a query needs to know beyond the task itself
+type Env interface {
+	IsBlocked(task *todo.Task) bool
+	IsOverdue(task *todo.Task) bool
+	Location() *time.Location
+}
+
+// matcher reports whether a task meets a condition
+type matcher func(task *todo.Task, env Env) bool
+
+// Query is a parsed filter expression
+type Query struct {
+	text  string
+	match matcher
+}
+
+// String returns the query as it was written
+func (q *Query) String() string {
+	return q.text
+}
+
+// Match reports whether the task meets the query
+func (q *Query) Match(task *todo.Task, env Env) bool {
+	return q.match(task, env)
+}
+
+// SyntaxError describes a query that cannot be parsed, and where
+type SyntaxError struct {
+	Query string
+	Pos   int // byte offset of the problem in Query
+	Msg   string
+}
+
+// Column returns the 1-based column of the problem
+func (e *SyntaxError) Column() int {
+	return utf8.RuneCountInString(e.Query[:e.Pos]) + 1
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Column(), e.Msg)
+}
+
+// Context returns the query with a caret under the problem
+func (e *SyntaxError) Context() string {
+	return e.Query + "\n" + strings.Repeat(" ", e.Column()-1) + "^"
+}
+
+// syntaxError creates a SyntaxError at pos
+func syntaxError(text string, pos int, msg string) *SyntaxError {
+	return &SyntaxError{Query: text, Pos: pos, Msg: msg}
+}
+
+// Parser parses queries, reading dates in them with Dates
+type Parser struct {
+	Dates *dateparse.Parser
+}
+
+// New creates a query parser that reads dates with dates
+func New(dates *dateparse.Parser) *Parser {
+	return &Parser{Dates: dates}
+}
+
+// Parse parses a query. A condition is either a field comparison or a word:
+//
+//	priority:high, priority>=medium    also pri; low < medium < high
+//	tag:ops, tag!=later                has (or lacks) the tag
+//	project:work                       in work or one of its sub-projects
+//	title:deploy, title="Deploy v2"    title contains, or equals
+//	id>10, parent:4, parent:none
+//	is:blocked, is!=done               states: done, pending, overdue,
+//	                                   blocked, recurring, subtask
+//	due<7d, due:today, due:none        also created and completed; dates
+//	                                   as in dateparse, such as fri, -7d
+//
+// Operators are : and = (equal), != and <, <=, >, >=. A word on its own is a
+// state (done, pending, overdue, ...) or else text the title must contain.
+// Values with spaces are double-quoted.
+func (p *Parser) Parse(text string) (*Query, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, syntaxError(text, 0, "empty query")
+	}
+
+	tokens, err := lex(text)
+	if err != nil {
+		return nil, err
+	}
+
+	dates := p.Dates
+	if dates == nil {
+		dates = dateparse.New()
+	}
+
+	ps := &parser{text: text, tokens: tokens, dates: dates}
+	match, err := ps.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := ps.peek(); t.kind != tokEOF {
+		if t.kind == tokRParen {
+			return nil, syntaxError(text, t.pos, "unexpected ')' without a matching '('")
+		}
+		return nil, syntaxError(text, t.pos, fmt.Sprintf("unexpected %s", t.describe()))
+	}
+
+	return &Query{text: text, match: match}, nil
+}
+
+// parser holds the state of parsing one query
+type parser struct {
+	text   string
+	tokens []token
+	pos    int
+	dates  *dateparse.Parser
+}
+
+// peek returns the next token without consuming it
+func (ps *parser) peek() token {
+	return ps.tokens[ps.pos]
+}
+
+// next consumes and returns the next token
+func (ps *parser) next() token {
+	t := ps.tokens[ps.pos]
+	if t.kind != tokEOF {
+		ps.pos++
+	}
+	return t
+}
+
+// errorAt returns a syntax error at token t
+func (ps *parser) errorAt(t token, format string, args ...any) error {
+	return syntaxError(ps.text, t.pos, fmt.Sprintf(format, args...))
+}
+
+// isKeyword reports whether t is the keyword word
+func isKeyword(t token, word string) bool {
+	return t.kind == tokWord && strings.EqualFold(t.text, word)
+}
+
+// parseOr parses conditions joined with or
+func (ps *parser) parseOr() (matcher, error) {
+	left, err := ps.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for isKeyword(ps.peek(), "or") {
+		ps.next()
+		right, err := ps.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = or(left, right)
+	}
+	return left, nil
+}
+
+// parseAnd parses conditions joined with and, or written side by side
+func (ps *parser) parseAnd() (matcher, error) {
+	left, err := ps.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := ps.peek()
+		if t.kind == tokEOF || t.kind == tokRParen || isKeyword(t, "or") {
+			return left, nil
+		}
+		if isKeyword(t, "and") {
+			ps.next()
+		}
+
+		right, err := ps.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = and(left, right)
+	}
+}
+
+// parseUnary parses a condition, possibly negated with not
+func (ps *parser) parseUnary() (matcher, error) {
+	if isKeyword(ps.peek(), "not") {
+		ps.next()
+		inner, err := ps.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return not(inner), nil
+	}
+	return ps.parsePrimary()
+}
+
+// parsePrimary parses a parenthesized query, a comparison or a word
+func (ps *parser) parsePrimary() (matcher, error) {
+	t := ps.next()
+
+	switch t.kind {
+	case tokEOF:
+		return nil, ps.errorAt(t, "unexpected end of query, expected a condition")
+
+	case tokRParen:
+		return nil, ps.errorAt(t, "unexpected ')', expected a condition")
+
+	case tokOp:
+		return nil, ps.errorAt(t, "unexpected '%s', expected a field name before it", t.text)
+
+	case tokLParen:
+		inner, err := ps.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := ps.next(); closing.kind != tokRParen {
+			return nil, ps.errorAt(closing, "expected ')' to close the '(' at column %d, found %s",
+				utf8.RuneCountInString(ps.text[:t.pos])+1, closing.describe())
+		}
+		return inner, nil
+
+	case tokString:
+		return titleContains(t.text), nil
+	}
+
+	if isKeyword(t, "and") || isKeyword(t, "or") {
+		return nil, ps.errorAt(t, "unexpected '%s', expected a condition", t.text)
+	}
+
+	if ps.peek().kind != tokOp {
+		return word(t.text), nil
+	}
+
+	op := ps.next()
+	value := ps.next()
+	if value.kind != tokWord && value.kind != tokString {
+		return nil, ps.errorAt(value, "expected a value after '%s%s', found %s", t.text, op.text, value.describe())
+	}
+	return ps.comparison(t, op, value)
+}
+
+// and matches tasks that meet both conditions
+func and(left, right matcher) matcher {
+	return func(task *todo.Task, env Env) bool {
+		return left(task, env) && right(task, env)
+	}
+}
+
+// or matches tasks that meet either condition
+func or(left, right matcher) matcher {
+	return func(task *todo.Task, env Env) bool {
+		return left(task, env) || right(task, env)
+	}
+}
+
+// not matches tasks that do not meet the condition
+func not(inner matcher) matcher {
+	return func(task *todo.Task, env Env) bool {
+		return !inner(task, env)
+	}
+}
@@ -0,0 +1,102 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/todo"
+)
+
+// testEnv is an Env where no task is blocked or overdue
+type testEnv struct{}
+
+func (testEnv) IsBlocked(task *todo.Task) bool { return false }
+func (testEnv) IsOverdue(task *todo.Task) bool { return false }
+func (testEnv) Location() *time.Location       { return time.UTC }
+
+func TestQueryPrecedence(t *testing.T) {
+	tasks := []*todo.Task{
+		{ID: 1, Title: "Deploy", Priority: todo.PriorityHigh, Tags: []string{"ops"}},
+		{ID: 2, Title: "Fix the fence", Priority: todo.PriorityLow, Tags: []string{"ops", "home"}},
+		{ID: 3, Title: "Water plants", Priority: todo.PriorityMedium, Tags: []string{"home"}},
+		{ID: 4, Title: "File taxes", Priority: todo.PriorityHigh, Completed: true},
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"tag:ops or tag:home and pri:high", []int{1, 2}},
+		{"(tag:ops or tag:home) and pri:high", []int{1}},
+		{"tag:ops or tag:home pri:medium", []int{1, 2, 3}},
+		{"tag:ops and tag:home or pri:medium", []int{2, 3}},
+		{"not tag:ops or done", []int{3, 4}},
+		{"not (tag:ops or done)", []int{3}},
+		{"not not tag:home", []int{2, 3}},
+		{"pri>=medium and not done", []int{1, 3}},
+		{"NOT done AND (tag:home OR title:deploy)", []int{1, 2, 3}},
+	}
+
+	parser := New(dateparse.New())
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := parser.Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.query, err)
+			}
+
+			var got []int
+			for _, task := range tasks {
+				if q.Match(task, testEnv{}) {
+					got = append(got, task.ID)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) matches %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuerySyntaxErrorColumns(t *testing.T) {
+	tests := []struct {
+		query   string
+		column  int
+		message string
+	}{
+		{"", 1, "empty query"},
+		{"tag:ops and", 12, "unexpected end of query"},
+		{"(tag:ops or done", 17, "expected ')' to close the '(' at column 1"},
+		{"tag:ops)", 8, "unexpected ')' without a matching '('"},
+		{"a or or b", 6, "unexpected 'or'"},
+		{"pri:urgent", 5, "unknown priority 'urgent'"},
+		{"tag<ops", 4, "operator '<' cannot be used with tag"},
+		{"titel:x", 1, "unknown field 'titel'"},
+		{"due<someday", 5, "invalid date 'someday'"},
+		{`title:"unterminated`, 7, "unterminated string"},
+		{"café !done", 6, "use 'not' to negate"},
+		{"déjà vu and is:later", 16, "unknown state 'later'"},
+	}
+
+	parser := New(dateparse.New())
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := parser.Parse(tt.query)
+
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Parse(%q) error = %v, want a SyntaxError", tt.query, err)
+			}
+			if got := syntaxErr.Column(); got != tt.column {
+				t.Errorf("Parse(%q) error at column %d, want %d (%v)", tt.query, got, tt.column, err)
+			}
+			if !strings.Contains(syntaxErr.Msg, tt.message) {
+				t.Errorf("Parse(%q) error = %q, want it to contain %q", tt.query, syntaxErr.Msg, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Package quickadd reads task fields written inline in a title, as in
+// "Call the plumber !high +home @house.repairs due:fri rec:monthly". The
+// tokens are taken out of the title; everything else is kept as written.
+package quickadd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/recur"
+	"todo-cli/internal/todo"
+)
+
+// escape marks a word that must be kept literally, as in `\+1` or `\due:x`
+const escape = `\`
+
+var priorities = map[string]todo.Priority{
+	"!high": todo.PriorityHigh, "!h": todo.PriorityHigh, "!!!": todo.PriorityHigh,
+	"!medium": todo.PriorityMedium, "!med": todo.PriorityMedium, "!m": todo.PriorityMedium, "!!": todo.PriorityMedium,
+	"!low": todo.PriorityLow, "!l": todo.PriorityLow,
+}
+
+// Result holds the title and the fields found in it. Fields that were not
+// given are left empty.
+type Result struct {
+	Title      string
+	Priority   todo.Priority
+	Tags       []string
+	Project    string
+	DueDate    *time.Time
+	DueAllDay  bool
+	Recurrence string   // as written, such as "weekly" or "every 2nd tuesday"
+	Tokens     []string // the tokens taken out of the title, in order
+}
+
+// Parser reads inline fields, resolving due dates with Dates
+type Parser struct {
+	Dates *dateparse.Parser
+}
+
+// New creates a parser that reads due dates with dates
+func New(dates *dateparse.Parser) *Parser {
+	return &Parser{Dates: dates}
+}
+
+// Parse takes the inline fields out of text:
+//
+//	!high, !medium, !low (or !h, !m, !l; !!! and !! for high and medium)
+//	+tag              repeatable
+//	@project          such as @work.backend
+//	due:fri           any date the dateparse package reads; quote dates
+//	                  with spaces: due:"next monday 9am"
+//	rec:weekly        a recurrence rule, quoted if it has spaces:
+//	                  rec:"every 2nd tuesday"
+//
+// A word starting with a backslash is kept in the title without it, so
+// `\+1` stays "+1". Giving the priority, project, due date or recurrence
+// twice is an error.
+func (p *Parser) Parse(text string) (*Result, error) {
+	return p.parseWords(splitWords(text))
+}
+
+// ParseArgs is Parse for command-line arguments. An argument that is a
+// whole token, such as `due:next monday` quoted in the shell, is read as
+// one token; other arguments are split into words.
+func (p *Parser) ParseArgs(args []string) (*Result, error) {
+	var words []string
+	for _, arg := range args {
+		if key, _, ok := strings.Cut(arg, ":"); ok && isValueKey(key) {
+			words = append(words, arg)
+		} else {
+			words = append(words, splitWords(arg)...)
+		}
+	}
+	return p.parseWords(words)
+}
+
+// parseWords takes the inline fields out of a title split into words
+func (p *Parser) parseWords(words []string) (*Result, error) {
+	result := &Result{}
+	var title []string
+
+	for _, word := range words {
+		if strings.HasPrefix(word, escape) && len(word) > 1 {
+			title = append(title, word[1:])
+			continue
+		}
+
+		taken, err := p.take(result, word)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			result.Tokens = append(result.Tokens, word)
+		} else {
+			title = append(title, word)
+		}
+	}
+
+	result.Title = strings.Join(title, " ")
+	return result, nil
+}
+
+// take sets the field word stands for, if any, and reports whether it did
+func (p *Parser) take(result *Result, word string) (bool, error) {
+	lower := strings.ToLower(word)
+
+	if priority, ok := priorities[lower]; ok {
+		if result.Priority != "" {
+			return false, fmt.Errorf("priority given twice: %s", word)
+		}
+		result.Priority = priority
+		return true, nil
+	}
+
+	if len(word) > 1 && strings.HasPrefix(word, "+") {
+		tag := todo.NormalizeTag(word)
+		if tag == "" {
+			return false, nil
+		}
+		for _, t := range result.Tags {
+			if t == tag {
+				return true, nil
+			}
+		}
+		result.Tags = append(result.Tags, tag)
+		return true, nil
+	}
+
+	if len(word) > 1 && strings.HasPrefix(word, "@") {
+		if result.Project != "" {
+			return false, fmt.Errorf("project given twice: %s", word)
+		}
+		project, err := todo.NormalizeProject(word[1:])
+		if err != nil {
+			return false, err
+		}
+		result.Project = project
+		return true, nil
+	}
+
+	key, value, ok := strings.Cut(word, ":")
+	if !ok || value == "" || !isValueKey(key) {
+		return false, nil
+	}
+	value = unquote(value)
+
+	switch strings.ToLower(key) {
+	case "due":
+		if result.DueDate != nil {
+			return false, fmt.Errorf("due date given twice: %s", word)
+		}
+		due, hasTime, err := p.dates().Parse(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid due date in %s: %w", word, err)
+		}
+		result.DueDate, result.DueAllDay = &due, !hasTime
+		return true, nil
+
+	case "rec":
+		if result.Recurrence != "" {
+			return false, fmt.Errorf("recurrence given twice: %s", word)
+		}
+		if _, err := recur.Parse(value); err != nil {
+			return false, fmt.Errorf("invalid recurrence in %s: %w", word, err)
+		}
+		result.Recurrence = value
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// isValueKey reports whether key starts a key:value token
+func isValueKey(key string) bool {
+	switch strings.ToLower(key) {
+	case "due", "rec":
+		return true
+	}
+	return false
+}
+
+// dates returns the parser's date parser
+func (p *Parser) dates() *dateparse.Parser {
+	if p.Dates == nil {
+		return dateparse.New()
+	}
+	return p.Dates
+}
+
+// AddOptions returns the parsed fields as options for adding the task
+func (r *Result) AddOptions() todo.AddOptions {
+	return todo.AddOptions{
+		Priority:   r.Priority,
+		DueDate:    r.DueDate,
+		DueAllDay:  r.DueAllDay,
+		Tags:       r.Tags,
+		Project:    r.Project,
+		Recurrence: r.Recurrence,
+	}
+}
+
+// splitWords splits text at whitespace, keeping a double-quoted part of a
+// word, as in due:"next monday", together with the rest of the word
+func splitWords(text string) []string {
+	var words []string
+	var word strings.Builder
+	inQuotes := false
+
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			word.WriteRune(r)
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		default:
+			word.WriteRune(r)
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// unquote removes the double quotes around a token value
+func unquote(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
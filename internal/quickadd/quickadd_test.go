@@ -0,0 +1,129 @@
+package quickadd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"todo-cli/internal/dateparse"
+	"todo-cli/internal/todo"
+)
+
+// testParser reads due dates relative to Wednesday 2026-01-07 09:00 UTC
+func testParser() *Parser {
+	now := time.Date(2026, time.January, 7, 9, 0, 0, 0, time.UTC)
+	return New(&dateparse.Parser{Now: func() time.Time { return now }, Location: time.UTC})
+}
+
+func TestParseEscapes(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		title      string
+		priority   todo.Priority
+		tags       []string
+		project    string
+		due        string // YYYY-MM-DD; empty for none
+		recurrence string
+		tokens     []string
+	}{
+		{
+			name:  "escaped tag",
+			args:  []string{`Pay \+1 fee`},
+			title: "Pay +1 fee",
+		},
+		{
+			name:  "escaped priority",
+			args:  []string{`\!high jump practice`},
+			title: "!high jump practice",
+		},
+		{
+			name:   "escaped project next to a real tag",
+			args:   []string{`email \@bob about +work`},
+			title:  "email @bob about",
+			tags:   []string{"work"},
+			tokens: []string{"+work"},
+		},
+		{
+			name:  "escaped key:value tokens",
+			args:  []string{`read \due:tomorrow and \rec:weekly notes`},
+			title: "read due:tomorrow and rec:weekly notes",
+		},
+		{
+			name:  "escaped backslash keeps one",
+			args:  []string{`path \\+x`},
+			title: `path \+x`,
+		},
+		{
+			name:  "lone backslash",
+			args:  []string{`a \ b`},
+			title: `a \ b`,
+		},
+		{
+			name:     "escaped and real token side by side",
+			args:     []string{`\+tag +tag !h`},
+			title:    "+tag",
+			priority: todo.PriorityHigh,
+			tags:     []string{"tag"},
+			tokens:   []string{"+tag", "!h"},
+		},
+		{
+			name:    "escaped word among separate arguments",
+			args:    []string{"Ship", `\@home`, "@work", "due:fri"},
+			title:   "Ship @home",
+			project: "work",
+			due:     "2026-01-09",
+			tokens:  []string{"@work", "due:fri"},
+		},
+		{
+			name:       "quoted values and an escape",
+			args:       []string{`call mom due:"next monday" \rec:weekly rec:"every 2 weeks"`},
+			title:      "call mom rec:weekly",
+			due:        "2026-01-12",
+			recurrence: "every 2 weeks",
+			tokens:     []string{`due:"next monday"`, `rec:"every 2 weeks"`},
+		},
+		{
+			name:     "escaped duplicate is not an error",
+			args:     []string{`!low \!low`},
+			title:    "!low",
+			priority: todo.PriorityLow,
+			tokens:   []string{"!low"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testParser().ParseArgs(tt.args)
+			if err != nil {
+				t.Fatalf("ParseArgs(%q) error = %v", tt.args, err)
+			}
+
+			if got.Title != tt.title {
+				t.Errorf("title = %q, want %q", got.Title, tt.title)
+			}
+			if got.Priority != tt.priority {
+				t.Errorf("priority = %q, want %q", got.Priority, tt.priority)
+			}
+			if !reflect.DeepEqual(got.Tags, tt.tags) {
+				t.Errorf("tags = %q, want %q", got.Tags, tt.tags)
+			}
+			if got.Project != tt.project {
+				t.Errorf("project = %q, want %q", got.Project, tt.project)
+			}
+			var due string
+			if got.DueDate != nil {
+				due = got.DueDate.Format("2006-01-02")
+			}
+			if due != tt.due {
+				t.Errorf("due = %q, want %q", due, tt.due)
+			}
+			if got.Recurrence != tt.recurrence {
+				t.Errorf("recurrence = %q, want %q", got.Recurrence, tt.recurrence)
+			}
+			if !reflect.DeepEqual(got.Tokens, tt.tokens) {
+				t.Errorf("tokens = %q, want %q", got.Tokens, tt.tokens)
+			}
+		})
+	}
+}
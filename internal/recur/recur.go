@@ -0,0 +1,556 @@
+// Package recur parses and evaluates task recurrence rules. Rules are stored
+// as a subset of the iCalendar RRULE format (FREQ, INTERVAL, BYDAY,
+// BYMONTHDAY, UNTIL and COUNT) and can be written in plain English, such as
+// "every 2 weeks on mon,fri" or "every 2nd tuesday".
+package recur
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the base unit a rule repeats in
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// untilFormat is the date format of UNTIL in stored rules
+const untilFormat = "20060102"
+
+// Weekday is a BYDAY entry: a day of the week, optionally limited to its
+// Nth (or, if negative, Nth last) occurrence in a month
+type Weekday struct {
+	Pos int // 0 for every occurrence
+	Day time.Weekday
+}
+
+// Rule describes when a task repeats
+type Rule struct {
+	Freq       Frequency
+	Interval   int // repeat every Interval units; at least 1
+	ByDay      []Weekday
+	ByMonthDay int       // day of the month for monthly rules; 0 for none
+	Until      time.Time // last possible date; zero for no end
+	Count      int       // occurrences left including the current one; 0 for no limit
+}
+
+var dayCodes = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+var dayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var ordinals = map[string]int{
+	"1st": 1, "first": 1, "2nd": 2, "second": 2, "3rd": 3, "third": 3,
+	"4th": 4, "fourth": 4, "5th": 5, "fifth": 5, "last": -1,
+}
+
+var units = map[string]Frequency{
+	"day": Daily, "days": Daily, "daily": Daily,
+	"week": Weekly, "weeks": Weekly, "weekly": Weekly,
+	"month": Monthly, "months": Monthly, "monthly": Monthly,
+	"year": Yearly, "years": Yearly, "yearly": Yearly, "annually": Yearly,
+}
+
+// Parse reads a rule written either as an RRULE ("FREQ=WEEKLY;BYDAY=MO") or
+// in plain English. Examples of the latter:
+//
+//	daily, weekly, monthly, yearly, weekdays
+//	every 3 days, every other week
+//	every monday, every mon,wed,fri, every 2 weeks on tue,thu
+//	every 2nd tuesday, every last friday, every month on the 15th
+//	... until 2026-06-30, ... 5 times
+func Parse(text string) (Rule, error) {
+	text = strings.TrimSpace(text)
+	upper := strings.ToUpper(text)
+	if strings.HasPrefix(upper, "FREQ=") || strings.HasPrefix(upper, "RRULE:") {
+		return ParseRRULE(text)
+	}
+
+	rule, err := parseEnglish(strings.ToLower(text))
+	if err != nil {
+		return Rule{}, err
+	}
+	return rule, rule.validate()
+}
+
+// ParseRRULE reads a rule in RRULE format
+func ParseRRULE(text string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	text = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(text)), "RRULE:")
+
+	for _, part := range strings.Split(text, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(value)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				day, dayErr := parseDayCode(code)
+				if dayErr != nil {
+					return Rule{}, dayErr
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = strconv.Atoi(value)
+		case "UNTIL":
+			if len(value) > len(untilFormat) {
+				value = value[:len(untilFormat)]
+			}
+			rule.Until, err = time.ParseInLocation(untilFormat, value, time.Local)
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(value)
+		default:
+			return Rule{}, fmt.Errorf("unsupported recurrence rule part %q", key)
+		}
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid %s in recurrence rule: %q", key, value)
+		}
+	}
+
+	return rule, rule.validate()
+}
+
+// parseDayCode reads a BYDAY entry such as "MO", "2TU" or "-1FR"
+func parseDayCode(code string) (Weekday, error) {
+	code = strings.TrimSpace(code)
+	if len(code) < 2 {
+		return Weekday{}, fmt.Errorf("invalid weekday %q in recurrence rule", code)
+	}
+
+	day, ok := dayCodes[code[len(code)-2:]]
+	if !ok {
+		return Weekday{}, fmt.Errorf("invalid weekday %q in recurrence rule", code)
+	}
+
+	pos := 0
+	if prefix := code[:len(code)-2]; prefix != "" {
+		var err error
+		pos, err = strconv.Atoi(prefix)
+		if err != nil || pos == 0 || pos < -5 || pos > 5 {
+			return Weekday{}, fmt.Errorf("invalid weekday %q in recurrence rule", code)
+		}
+	}
+	return Weekday{Pos: pos, Day: day}, nil
+}
+
+// parseEnglish reads a plain-English rule
+func parseEnglish(text string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	words := strings.Fields(strings.NewReplacer(",", " , ").Replace(text))
+
+	// Trailing "until <date>" and "<n> times"
+	for len(words) >= 2 {
+		last := len(words) - 1
+		if words[last-1] == "until" {
+			until, err := time.ParseInLocation("2006-01-02", words[last], time.Local)
+			if err != nil {
+				return Rule{}, fmt.Errorf("invalid end date %q in recurrence rule, use YYYY-MM-DD", words[last])
+			}
+			rule.Until = until
+			words = words[:last-1]
+			continue
+		}
+		if words[last] == "times" || words[last] == "time" {
+			count, err := strconv.Atoi(words[last-1])
+			if err != nil || count <= 0 {
+				return Rule{}, fmt.Errorf("invalid count %q in recurrence rule", words[last-1])
+			}
+			rule.Count = count
+			words = words[:last-1]
+			continue
+		}
+		break
+	}
+
+	if len(words) > 0 && words[0] == "every" {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return Rule{}, fmt.Errorf("empty recurrence rule")
+	}
+
+	// Interval: "3 days", "other week"
+	if n, err := strconv.Atoi(words[0]); err == nil && len(words) > 1 {
+		rule.Interval = n
+		words = words[1:]
+	} else if words[0] == "other" && len(words) > 1 {
+		rule.Interval = 2
+		words = words[1:]
+	}
+
+	switch {
+	case words[0] == "weekday" || words[0] == "weekdays":
+		rule.Freq = Weekly
+		for _, day := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday} {
+			rule.ByDay = append(rule.ByDay, Weekday{Day: day})
+		}
+		words = words[1:]
+
+	case words[0] == "weekend" || words[0] == "weekends":
+		rule.Freq = Weekly
+		rule.ByDay = []Weekday{{Day: time.Saturday}, {Day: time.Sunday}}
+		words = words[1:]
+
+	case ordinals[words[0]] != 0 && len(words) > 1 && isDayName(words[1]):
+		// "2nd tuesday", "last friday"
+		rule.Freq = Monthly
+		rule.ByDay = []Weekday{{Pos: ordinals[words[0]], Day: dayNames[words[1]]}}
+		words = words[2:]
+		if len(words) >= 3 && words[0] == "of" && words[1] == "the" && words[2] == "month" {
+			words = words[3:]
+		}
+
+	case isDayName(words[0]):
+		rule.Freq = Weekly
+		days, rest := parseDayList(words)
+		rule.ByDay, words = days, rest
+
+	case units[words[0]] != "":
+		rule.Freq = units[words[0]]
+		words = words[1:]
+
+	default:
+		return Rule{}, fmt.Errorf("unrecognized recurrence rule %q", text)
+	}
+
+	// Optional "on ..." qualifier
+	if len(words) > 0 && words[0] == "on" {
+		words = words[1:]
+		switch {
+		case rule.Freq == Weekly && len(words) > 0 && isDayName(words[0]):
+			days, rest := parseDayList(words)
+			rule.ByDay, words = days, rest
+
+		case rule.Freq == Monthly:
+			if len(words) > 0 && words[0] == "the" {
+				words = words[1:]
+			}
+			if len(words) >= 2 && ordinals[words[0]] != 0 && isDayName(words[1]) {
+				rule.ByDay = []Weekday{{Pos: ordinals[words[0]], Day: dayNames[words[1]]}}
+				words = words[2:]
+			} else if len(words) > 0 {
+				day, err := parseMonthDay(words[0])
+				if err != nil {
+					return Rule{}, err
+				}
+				rule.ByMonthDay = day
+				words = words[1:]
+			}
+		}
+	}
+
+	if len(words) > 0 {
+		return Rule{}, fmt.Errorf("unrecognized recurrence rule %q", text)
+	}
+	return rule, nil
+}
+
+// isDayName reports whether word names a day of the week
+func isDayName(word string) bool {
+	_, ok := dayNames[word]
+	return ok
+}
+
+// parseDayList reads day names separated by commas or "and"
+func parseDayList(words []string) ([]Weekday, []string) {
+	var days []Weekday
+	for len(words) > 0 {
+		switch {
+		case words[0] == "," || words[0] == "and":
+		case isDayName(words[0]):
+			days = append(days, Weekday{Day: dayNames[words[0]]})
+		default:
+			return days, words
+		}
+		words = words[1:]
+	}
+	return days, words
+}
+
+// parseMonthDay reads a day of the month such as "15", "15th" or "1st"
+func parseMonthDay(word string) (int, error) {
+	word = strings.TrimRight(word, "stndrh")
+	day, err := strconv.Atoi(word)
+	if err != nil || day < 1 || day > 31 {
+		return 0, fmt.Errorf("invalid day of the month %q in recurrence rule", word)
+	}
+	return day, nil
+}
+
+// validate checks that the rule is complete and consistent
+func (r Rule) validate() error {
+	switch r.Freq {
+	case Daily, Weekly, Monthly, Yearly:
+	case "":
+		return fmt.Errorf("recurrence rule has no frequency")
+	default:
+		return fmt.Errorf("unsupported recurrence frequency %q", r.Freq)
+	}
+
+	if r.Interval < 1 {
+		return fmt.Errorf("recurrence interval must be at least 1")
+	}
+	if r.Count < 0 {
+		return fmt.Errorf("recurrence count cannot be negative")
+	}
+	if r.ByMonthDay < 0 || r.ByMonthDay > 31 {
+		return fmt.Errorf("invalid day of the month %d in recurrence rule", r.ByMonthDay)
+	}
+
+	for _, day := range r.ByDay {
+		if day.Pos != 0 && r.Freq != Monthly {
+			return fmt.Errorf("numbered weekdays such as 2TU need a monthly rule")
+		}
+	}
+	if len(r.ByDay) > 0 && r.Freq != Weekly && r.Freq != Monthly {
+		return fmt.Errorf("weekdays need a weekly or monthly rule")
+	}
+	if r.ByMonthDay != 0 && r.Freq != Monthly {
+		return fmt.Errorf("a day of the month needs a monthly rule")
+	}
+	return nil
+}
+
+// String returns the rule in RRULE format
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			codes[i] = day.code()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.Format(untilFormat))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// code returns the BYDAY code of the weekday, such as "MO" or "-1FR"
+func (d Weekday) code() string {
+	code := strings.ToUpper(d.Day.String()[:2])
+	if d.Pos != 0 {
+		code = strconv.Itoa(d.Pos) + code
+	}
+	return code
+}
+
+// Describe returns the rule in plain English, such as "every 2 weeks on Mon, Fri"
+func (r Rule) Describe() string {
+	unit := map[Frequency]string{Daily: "day", Weekly: "week", Monthly: "month", Yearly: "year"}[r.Freq]
+
+	var b strings.Builder
+	if r.Interval > 1 {
+		fmt.Fprintf(&b, "every %d %ss", r.Interval, unit)
+	} else {
+		fmt.Fprintf(&b, "every %s", unit)
+	}
+
+	if len(r.ByDay) > 0 {
+		names := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			names[i] = day.Day.String()[:3]
+			if day.Pos != 0 {
+				names[i] = ordinalName(day.Pos) + " " + day.Day.String()
+			}
+		}
+		b.WriteString(" on " + strings.Join(names, ", "))
+	}
+	if r.ByMonthDay != 0 {
+		fmt.Fprintf(&b, " on day %d", r.ByMonthDay)
+	}
+	if !r.Until.IsZero() {
+		b.WriteString(" until " + r.Until.Format("2006-01-02"))
+	}
+	if r.Count > 0 {
+		fmt.Fprintf(&b, " (%d left)", r.Count)
+	}
+	return b.String()
+}
+
+// ordinalName returns "1st", "2nd", ... or "last"
+func ordinalName(pos int) string {
+	switch pos {
+	case -1:
+		return "last"
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	}
+	if pos < 0 {
+		return fmt.Sprintf("%d from last", -pos)
+	}
+	return strconv.Itoa(pos) + "th"
+}
+
+// Next returns the first occurrence strictly after from, at the same time of
+// day, and the rule to store with it (with COUNT reduced). ok is false when
+// the rule has no more occurrences.
+func (r Rule) Next(from time.Time) (next time.Time, rest Rule, ok bool) {
+	if r.Count == 1 {
+		return time.Time{}, r, false
+	}
+
+	next = r.next(from)
+	if next.IsZero() {
+		return time.Time{}, r, false
+	}
+
+	if !r.Until.IsZero() {
+		y, m, d := r.Until.Date()
+		if next.After(time.Date(y, m, d, 23, 59, 59, 0, next.Location())) {
+			return time.Time{}, r, false
+		}
+	}
+
+	rest = r
+	if rest.Count > 0 {
+		rest.Count--
+	}
+	return next, rest, true
+}
+
+// next computes the next occurrence after from, ignoring UNTIL and COUNT
+func (r Rule) next(from time.Time) time.Time {
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, from.Hour(), from.Minute(), from.Second(), 0, from.Location())
+	}
+
+	switch r.Freq {
+	case Daily:
+		return from.AddDate(0, 0, r.Interval)
+
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return from.AddDate(0, 0, 7*r.Interval)
+		}
+		startWeek := weekStart(from)
+		for i := 1; i <= 7*(r.Interval+1); i++ {
+			day := from.AddDate(0, 0, i)
+			weeks := int(weekStart(day).Sub(startWeek).Hours()+12) / (24 * 7)
+			if weeks%r.Interval == 0 && r.hasDay(day.Weekday()) {
+				return day
+			}
+		}
+
+	case Monthly:
+		y, m, _ := from.Date()
+		// Search far enough ahead for the 5th weekday of a month to come round
+		for k := 0; k <= 60*r.Interval; k += r.Interval {
+			first := time.Date(y, m+time.Month(k), 1, 0, 0, 0, 0, from.Location())
+			for _, d := range r.monthDays(first, from.Day()) {
+				candidate := at(first.Year(), first.Month(), d)
+				if candidate.After(from) {
+					return candidate
+				}
+			}
+		}
+
+	case Yearly:
+		y, m, d := from.Date()
+		// Feb 29 falls on Feb 28 in other years
+		return at(y+r.Interval, m, min(d, daysIn(y+r.Interval, m)))
+	}
+
+	return time.Time{}
+}
+
+// monthDays returns the days of the month starting at first that match the
+// rule, in order. defaultDay is used when the rule names no days.
+func (r Rule) monthDays(first time.Time, defaultDay int) []int {
+	last := daysIn(first.Year(), first.Month())
+
+	if len(r.ByDay) == 0 {
+		day := r.ByMonthDay
+		if day == 0 {
+			day = defaultDay
+		}
+		return []int{min(day, last)}
+	}
+
+	var days []int
+	for _, wd := range r.ByDay {
+		// Every matching weekday in the month
+		var matches []int
+		for d := 1; d <= last; d++ {
+			if time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, time.UTC).Weekday() == wd.Day {
+				matches = append(matches, d)
+			}
+		}
+
+		switch {
+		case wd.Pos == 0:
+			days = append(days, matches...)
+		case wd.Pos > 0 && wd.Pos <= len(matches):
+			days = append(days, matches[wd.Pos-1])
+		case wd.Pos < 0 && -wd.Pos <= len(matches):
+			days = append(days, matches[len(matches)+wd.Pos])
+		}
+	}
+	sort.Ints(days)
+	return days
+}
+
+// hasDay reports whether the rule includes the weekday
+func (r Rule) hasDay(day time.Weekday) bool {
+	for _, wd := range r.ByDay {
+		if wd.Day == day {
+			return true
+		}
+	}
+	return false
+}
+
+// weekStart returns midnight on the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.AddDate(0, 0, -offset).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// daysIn returns the number of days in a month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
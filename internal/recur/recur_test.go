@@ -0,0 +1,126 @@
+package recur
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// occurrenceLayout is how occurrences are written in the tests
+const occurrenceLayout = "2006-01-02 15:04"
+
+// occurrences follows the rule from start, returning at most max occurrences
+func occurrences(t *testing.T, rule Rule, start time.Time, max int) []string {
+	t.Helper()
+
+	var got []string
+	from := rule
+	at := start
+	for len(got) < max {
+		next, rest, ok := from.Next(at)
+		if !ok {
+			break
+		}
+		if !next.After(at) {
+			t.Fatalf("Next(%v) = %v, want a later time", at, next)
+		}
+		got = append(got, next.Format(occurrenceLayout))
+		from, at = rest, next
+	}
+	return got
+}
+
+func TestNextMonthEnd(t *testing.T) {
+	tests := []struct {
+		rule  string
+		start string
+		max   int
+		want  []string
+	}{
+		{"monthly", "2026-01-31 09:00", 1, []string{"2026-02-28 09:00"}},
+		{"monthly", "2028-01-31 09:00", 1, []string{"2028-02-29 09:00"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-31 09:00", 4, []string{
+			"2026-02-28 09:00", "2026-03-31 09:00", "2026-04-30 09:00", "2026-05-31 09:00",
+		}},
+		{"every month on the 30th", "2028-01-30 18:30", 2, []string{"2028-02-29 18:30", "2028-03-30 18:30"}},
+		{"every 3 months on the 31st", "2026-01-31 00:00", 2, []string{"2026-04-30 00:00", "2026-07-31 00:00"}},
+		{"every last friday", "2026-01-30 09:00", 3, []string{"2026-02-27 09:00", "2026-03-27 09:00", "2026-04-24 09:00"}},
+		{"FREQ=MONTHLY;BYDAY=5MO", "2026-01-01 09:00", 2, []string{"2026-03-30 09:00", "2026-06-29 09:00"}},
+		{"yearly", "2028-02-29 09:00", 2, []string{"2029-02-28 09:00", "2030-02-28 09:00"}},
+		{"daily", "2026-12-31 23:00", 1, []string{"2027-01-01 23:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule+" from "+tt.start, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			start, err := time.Parse(occurrenceLayout, tt.start)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := occurrences(t, rule, start, tt.max); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("occurrences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextCountAndUntil(t *testing.T) {
+	tests := []struct {
+		rule  string
+		start string
+		want  []string
+	}{
+		// COUNT includes the current occurrence
+		{"FREQ=DAILY;COUNT=3", "2026-01-01 09:00", []string{"2026-01-02 09:00", "2026-01-03 09:00"}},
+		{"every week 2 times", "2026-01-01 09:00", []string{"2026-01-08 09:00"}},
+		{"FREQ=DAILY;COUNT=1", "2026-01-01 09:00", nil},
+
+		// UNTIL includes the whole of its day
+		{"every day until 2026-01-03", "2026-01-01 23:30", []string{"2026-01-02 23:30", "2026-01-03 23:30"}},
+		{"FREQ=DAILY;UNTIL=20260101", "2026-01-01 09:00", nil},
+		{"FREQ=MONTHLY;BYMONTHDAY=31;UNTIL=20260430", "2026-01-31 09:00", []string{
+			"2026-02-28 09:00", "2026-03-31 09:00", "2026-04-30 09:00",
+		}},
+
+		// Whichever limit comes first ends the rule
+		{"FREQ=WEEKLY;UNTIL=20260114;COUNT=5", "2026-01-01 09:00", []string{"2026-01-08 09:00"}},
+		{"FREQ=WEEKLY;UNTIL=20261231;COUNT=2", "2026-01-01 09:00", []string{"2026-01-08 09:00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+			start, err := time.ParseInLocation(occurrenceLayout, tt.start, time.Local)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Ask for more than any rule allows, so the limit ends the list
+			if got := occurrences(t, rule, start, 20); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("occurrences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextReducesCount(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=MO,TH;COUNT=4")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	_, rest, ok := rule.Next(time.Date(2026, time.January, 5, 9, 0, 0, 0, time.UTC))
+	if !ok {
+		t.Fatal("Next() ok = false, want true")
+	}
+	if want := "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=3"; rest.String() != want {
+		t.Errorf("rest = %s, want %s", rest, want)
+	}
+}
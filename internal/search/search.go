@@ -0,0 +1,354 @@
+// Package search finds and ranks tasks matching search text such as
+//
+//	deploy "release notes" ops
+//
+// Every term must match the title or a tag. Terms match whole words,
+// word prefixes and substrings, and longer terms also match words with a
+// typo or two; quoted phrases must appear as written. Matches are scored so
+// the best results can be listed first, and the matched parts of a title
+// can be highlighted.
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Scores of a term matching in a title; tag matches score a bit less
+const (
+	scoreWord      = 10 // the term is a whole word of the title
+	scorePhrase    = 10 // a quoted phrase appears in the title
+	scorePrefix    = 7  // the term starts a word
+	scoreSubstring = 5  // the term appears inside a word
+	scoreFuzzy     = 3  // a word is one edit away; two edits score 1
+	scoreRegex     = 10 // a regular expression matches
+	tagPenalty     = 2  // subtracted from the score of a tag match
+)
+
+// Document is the searchable text of a task
+type Document struct {
+	Title string
+	Tags  []string
+}
+
+// Span is a matched part of a text, as byte offsets
+type Span struct {
+	Start, End int
+}
+
+// term is a word or quoted phrase of the search text
+type term struct {
+	text    string // lower-cased
+	phrase  bool
+	pattern *regexp.Regexp // finds the term anywhere, ignoring case
+}
+
+// Query is compiled search text
+type Query struct {
+	text  string
+	terms []term
+	regex *regexp.Regexp // set for regular expression searches
+}
+
+// Compile compiles search text: words and double-quoted phrases, all of
+// which must match. With regex set the text is a regular expression
+// instead, matched without regard to case unless it says otherwise.
+func Compile(text string, regex bool) (*Query, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, fmt.Errorf("empty search")
+	}
+
+	if regex {
+		re, err := regexp.Compile("(?i)" + text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %w", err)
+		}
+		return &Query{text: text, regex: re}, nil
+	}
+
+	parts, err := splitTerms(text)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{text: text}
+	for _, w := range parts {
+		q.terms = append(q.terms, term{
+			text:    strings.ToLower(w.text),
+			phrase:  w.phrase,
+			pattern: regexp.MustCompile("(?i)" + regexp.QuoteMeta(w.text)),
+		})
+	}
+	if len(q.terms) == 0 {
+		return nil, fmt.Errorf("empty search")
+	}
+	return q, nil
+}
+
+// String returns the search text as it was written
+func (q *Query) String() string {
+	return q.text
+}
+
+// Score reports how well a document matches, or zero if it does not
+func (q *Query) Score(doc Document) int {
+	if q.regex != nil {
+		switch {
+		case q.regex.MatchString(doc.Title):
+			return scoreRegex
+		case anyTag(doc.Tags, q.regex.MatchString):
+			return scoreRegex - tagPenalty
+		}
+		return 0
+	}
+
+	total := 0
+	for _, t := range q.terms {
+		best := t.score(doc.Title)
+		for _, tag := range doc.Tags {
+			if s := t.score(tag) - tagPenalty; s > best {
+				best = s
+			}
+		}
+		if best <= 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+// Matches reports whether a document matches
+func (q *Query) Matches(doc Document) bool {
+	return q.Score(doc) > 0
+}
+
+// Highlight returns the parts of text the query matches, in order and
+// without overlaps
+func (q *Query) Highlight(text string) []Span {
+	var spans []Span
+
+	if q.regex != nil {
+		for _, loc := range q.regex.FindAllStringIndex(text, -1) {
+			if loc[1] > loc[0] {
+				spans = append(spans, Span{loc[0], loc[1]})
+			}
+		}
+		return mergeSpans(spans)
+	}
+
+	for _, t := range q.terms {
+		found := t.pattern.FindAllStringIndex(text, -1)
+		for _, loc := range found {
+			spans = append(spans, Span{loc[0], loc[1]})
+		}
+		if len(found) == 0 && !t.phrase {
+			for _, w := range words(text) {
+				if fuzzyMatch(t.text, strings.ToLower(w.text)) > 0 {
+					spans = append(spans, Span{w.start, w.start + len(w.text)})
+				}
+			}
+		}
+	}
+	return mergeSpans(spans)
+}
+
+// score reports how well the term matches text, or zero if it does not
+func (t term) score(text string) int {
+	loc := t.pattern.FindStringIndex(text)
+	if t.phrase {
+		if loc == nil {
+			return 0
+		}
+		return scorePhrase
+	}
+
+	if loc != nil {
+		best := scoreSubstring
+		for _, w := range words(text) {
+			lower := strings.ToLower(w.text)
+			switch {
+			case lower == t.text:
+				return scoreWord
+			case strings.HasPrefix(lower, t.text):
+				best = scorePrefix
+			}
+		}
+		return best
+	}
+
+	best := 0
+	for _, w := range words(text) {
+		if s := fuzzyMatch(t.text, strings.ToLower(w.text)); s > best {
+			best = s
+		}
+	}
+	return best
+}
+
+// fuzzyMatch scores a word that is within a typo or two of term: one edit
+// for terms of four to seven letters, two for longer ones. Shorter terms
+// only match exactly.
+func fuzzyMatch(term, word string) int {
+	n := utf8.RuneCountInString(term)
+	allowed := 0
+	switch {
+	case n >= 8:
+		allowed = 2
+	case n >= 4:
+		allowed = 1
+	}
+	if allowed == 0 {
+		return 0
+	}
+
+	switch d := distance(term, word, allowed); {
+	case d > allowed:
+		return 0
+	case d == 2:
+		return scoreFuzzy - 2
+	}
+	return scoreFuzzy
+}
+
+// distance returns the edit distance between a and b, counting a swap of
+// two neighbouring letters as one edit, or a value above limit if it is
+// larger than limit
+func distance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > limit {
+		return limit + 1
+	}
+
+	// Three rows of the dynamic programming table are enough
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+// word is a word of a text and its byte offset
+type word struct {
+	text  string
+	start int
+}
+
+// words splits text into words of letters and digits
+func words(text string) []word {
+	var result []word
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			result = append(result, word{text: text[start:i], start: start})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		result = append(result, word{text: text[start:], start: start})
+	}
+	return result
+}
+
+// searchTerm is a word or phrase of the search text before compiling
+type searchTerm struct {
+	text   string
+	phrase bool
+}
+
+// splitTerms splits search text into words and double-quoted phrases
+func splitTerms(text string) ([]searchTerm, error) {
+	var terms []searchTerm
+	for {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return terms, nil
+		}
+
+		if strings.HasPrefix(text, `"`) {
+			end := strings.IndexByte(text[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated phrase: %s", text)
+			}
+			if phrase := strings.TrimSpace(text[1 : end+1]); phrase != "" {
+				terms = append(terms, searchTerm{text: phrase, phrase: true})
+			}
+			text = text[end+2:]
+			continue
+		}
+
+		end := strings.IndexFunc(text, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+		if end < 0 {
+			end = len(text)
+		}
+		terms = append(terms, searchTerm{text: text[:end]})
+		text = text[end:]
+	}
+}
+
+// mergeSpans sorts spans and joins those that overlap or touch
+func mergeSpans(spans []Span) []Span {
+	if len(spans) == 0 {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+
+	merged := []Span{spans[0]}
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.Start <= last.End {
+			last.End = max(last.End, s.End)
+		} else {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+// anyTag reports whether match is true for one of the tags
+func anyTag(tags []string, match func(string) bool) bool {
+	for _, tag := range tags {
+		if match(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,101 @@
+package search
+
+import "testing"
+
+func TestFuzzyMatchLimits(t *testing.T) {
+	tests := []struct {
+		term, word string
+		want       int
+	}{
+		// Terms of up to three letters only match exactly
+		{"cat", "cut", 0},
+		{"cat", "cta", 0},
+
+		// Four to seven letters allow one edit
+		{"task", "tusk", scoreFuzzy},
+		{"deploy", "deplay", scoreFuzzy},
+		{"deploy", "dpeloy", scoreFuzzy},
+		{"deploy", "deploys", scoreFuzzy},
+		{"deploy", "deplo", scoreFuzzy},
+		{"deploy", "dploay", 0},
+		{"deploy", "deploying", 0},
+		{"grocery", "grocry", scoreFuzzy},
+		{"grocery", "grcry", 0},
+
+		// Eight letters or more allow two, scoring less
+		{"database", "databse", scoreFuzzy},
+		{"database", "dtabse", scoreFuzzy - 2},
+		{"database", "dtbse", 0},
+		{"database", "databases12", 0},
+
+		// Letters are counted, not bytes
+		{"café", "cafe", scoreFuzzy},
+		{"résumé", "resume", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.term+"/"+tt.word, func(t *testing.T) {
+			if got := fuzzyMatch(tt.term, tt.word); got != tt.want {
+				t.Errorf("fuzzyMatch(%q, %q) = %d, want %d", tt.term, tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"kitten", "sitting", 5, 3},
+		{"kitten", "sitting", 2, 3}, // above the limit: limit+1
+		{"ab", "ba", 2, 1},
+		{"abcd", "badc", 2, 2},
+		{"", "abc", 5, 3},
+		{"", "abc", 2, 3},
+		{"same", "same", 0, 0},
+		{"same", "some", 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := distance(tt.a, tt.b, tt.limit); got != tt.want {
+				t.Errorf("distance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreFuzzyInTitlesAndTags(t *testing.T) {
+	tests := []struct {
+		search string
+		doc    Document
+		want   int
+	}{
+		{"deplyo", Document{Title: "Deploy the app"}, scoreFuzzy},
+		{"deplyo", Document{Title: "Fix bug", Tags: []string{"deploy"}}, scoreFuzzy - tagPenalty},
+		{"dtabse", Document{Title: "Back up database"}, 0},
+		{"databse", Document{Title: "Back up database"}, scoreFuzzy},
+		{"dtabasse", Document{Title: "Back up database"}, scoreFuzzy - 2},
+
+		// A two-edit match in a tag scores nothing after the tag penalty
+		{"dtabasse", Document{Title: "Back up", Tags: []string{"database"}}, 0},
+
+		// Every term must match
+		{"deplyo prod", Document{Title: "Deploy the app"}, 0},
+		{`"deplyo"`, Document{Title: "Deploy the app"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			q, err := Compile(tt.search, false)
+			if err != nil {
+				t.Fatalf("Compile(%q) error = %v", tt.search, err)
+			}
+			if got := q.Score(tt.doc); got != tt.want {
+				t.Errorf("Compile(%q).Score(%+v) = %d, want %d", tt.search, tt.doc, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"todo-cli/storage"
+)
+
+func TestCompleteAndReopenTask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	m := NewManagerWithBackend(storage.NewFileStorage(path))
+
+	task, err := m.AddTask("File taxes", PriorityHigh, nil)
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if task.CompletedAt != nil {
+		t.Fatalf("new task completed at %v", task.CompletedAt)
+	}
+	if _, err := m.ReopenTask(task.ID); err == nil {
+		t.Error("ReopenTask() of a pending task succeeded, want an error")
+	}
+
+	before := time.Now()
+	result, err := m.CompleteTask(task.ID)
+	if err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	after := time.Now()
+
+	completedAt := result.Task.CompletedAt
+	if !result.Task.Completed || completedAt == nil || completedAt.Before(before) || completedAt.After(after) {
+		t.Fatalf("completed %v at %v, want completed between %v and %v", result.Task.Completed, completedAt, before, after)
+	}
+	if _, err := m.CompleteTask(task.ID); err == nil {
+		t.Error("CompleteTask() of a completed task succeeded, want an error")
+	}
+
+	// The completion time is saved, not taken from the last update
+	stored, err := NewManagerWithBackend(storage.NewFileStorage(path)).GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if stored.CompletedAt == nil || !stored.CompletedAt.Equal(*completedAt) {
+		t.Errorf("stored completion time = %v, want %v", stored.CompletedAt, completedAt)
+	}
+
+	reopened, err := m.ReopenTask(task.ID)
+	if err != nil {
+		t.Fatalf("ReopenTask() error = %v", err)
+	}
+	if reopened.Completed || reopened.CompletedAt != nil {
+		t.Errorf("reopened task completed %v at %v, want pending without a completion time", reopened.Completed, reopened.CompletedAt)
+	}
+
+	stored, err = NewManagerWithBackend(storage.NewFileStorage(path)).GetTask(task.ID)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if stored.Completed || stored.CompletedAt != nil {
+		t.Errorf("stored reopened task completed %v at %v, want pending", stored.Completed, stored.CompletedAt)
+	}
+
+	// Completing again records the new time
+	time.Sleep(time.Millisecond)
+	result, err = m.CompleteTask(task.ID)
+	if err != nil {
+		t.Fatalf("CompleteTask() again error = %v", err)
+	}
+	if !result.Task.CompletedAt.After(*completedAt) {
+		t.Errorf("completed again at %v, want after %v", result.Task.CompletedAt, completedAt)
+	}
+}
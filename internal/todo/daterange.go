@@ -0,0 +1,72 @@
+package todo
+
+import "time"
+
+// TimeRange selects times from From (inclusive) up to To (exclusive). A nil
+// end leaves that side open.
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// IsZero reports whether the range is open on both sides
+func (r TimeRange) IsZero() bool {
+	return r.From == nil && r.To == nil
+}
+
+// Contains reports whether t falls within the range
+func (r TimeRange) Contains(t time.Time) bool {
+	if r.From != nil && t.Before(*r.From) {
+		return false
+	}
+	if r.To != nil && !t.Before(*r.To) {
+		return false
+	}
+	return true
+}
+
+// allDaySlack is the largest gap between midnight of the same day in two
+// time zones (UTC+14 and UTC-12)
+const allDaySlack = 26 * time.Hour
+
+// widen returns the range extended by d on each bounded side
+func (r TimeRange) widen(d time.Duration) TimeRange {
+	var widened TimeRange
+	if r.From != nil {
+		from := r.From.Add(-d)
+		widened.From = &from
+	}
+	if r.To != nil {
+		to := r.To.Add(d)
+		widened.To = &to
+	}
+	return widened
+}
+
+// matchesDates reports whether a task passes the date filters. An all-day
+// due date counts from the start of its day in the manager's time zone.
+func (m *Manager) matchesDates(task *Task, filter FilterOptions) bool {
+	if filter.NoDue && task.DueDate != nil {
+		return false
+	}
+	if filter.Overdue && !m.IsOverdue(task) {
+		return false
+	}
+
+	if !filter.Due.IsZero() && (task.DueDate == nil || !filter.Due.Contains(m.dueAt(task))) {
+		return false
+	}
+	if !filter.Created.IsZero() && !filter.Created.Contains(task.CreatedAt) {
+		return false
+	}
+	if !filter.Completed.IsZero() && (task.CompletedAt == nil || !filter.Completed.Contains(*task.CompletedAt)) {
+		return false
+	}
+	return true
+}
+
+// StatsFor returns the same statistics as GetStats for the given tasks,
+// such as those returned by ListTasks
+func (m *Manager) StatsFor(tasks []*Task) map[string]int {
+	return taskStats(tasks, m.now())
+}
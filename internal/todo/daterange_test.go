@@ -0,0 +1,76 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"todo-cli/storage"
+)
+
+func TestMatchesDates(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(day, hour int) *time.Time {
+		t := time.Date(2026, time.March, day, hour, 0, 0, 0, berlin)
+		return &t
+	}
+	between := func(from, to *time.Time) TimeRange { return TimeRange{From: from, To: to} }
+
+	timed := &Task{DueDate: at(10, 9), CreatedAt: *at(1, 12), CompletedAt: at(12, 18), Completed: true}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Set in Tokyo, its day starts at 16:00 the day before in Berlin
+	tokyoDay := time.Date(2026, time.March, 10, 0, 0, 0, 0, tokyo)
+	allDay := &Task{DueDate: &tokyoDay, DueAllDay: true, DueZone: "Asia/Tokyo", CreatedAt: *at(1, 12)}
+	undated := &Task{CreatedAt: *at(1, 12)}
+
+	tests := []struct {
+		name   string
+		task   *Task
+		filter FilterOptions
+		want   bool
+	}{
+		// From is inclusive, To exclusive
+		{"due at From", timed, FilterOptions{Due: between(at(10, 9), nil)}, true},
+		{"due just before From", timed, FilterOptions{Due: between(at(10, 10), nil)}, false},
+		{"due at To", timed, FilterOptions{Due: between(nil, at(10, 9))}, false},
+		{"due just before To", timed, FilterOptions{Due: between(nil, at(10, 10))}, true},
+		{"due within", timed, FilterOptions{Due: between(at(10, 0), at(11, 0))}, true},
+		{"no due date never matches a due range", undated, FilterOptions{Due: between(nil, at(31, 0))}, false},
+
+		// An all-day task counts from the start of its day in the manager's zone
+		{"all day at the start of its day", allDay, FilterOptions{Due: between(at(10, 0), at(11, 0))}, true},
+		{"all day before a range starting later that day", allDay, FilterOptions{Due: between(at(10, 1), nil)}, false},
+		{"all day at To", allDay, FilterOptions{Due: between(nil, at(10, 0))}, false},
+		{"all day not on the day before", allDay, FilterOptions{Due: between(at(9, 0), at(10, 0))}, false},
+
+		{"created at From", timed, FilterOptions{Created: between(at(1, 12), nil)}, true},
+		{"created at To", timed, FilterOptions{Created: between(nil, at(1, 12))}, false},
+		{"completed at From", timed, FilterOptions{Completed: between(at(12, 18), at(13, 0))}, true},
+		{"completed at To", timed, FilterOptions{Completed: between(at(12, 0), at(12, 18))}, false},
+		{"pending never matches a completion range", allDay, FilterOptions{Completed: between(at(1, 0), nil)}, false},
+
+		{"no due date", undated, FilterOptions{NoDue: true}, true},
+		{"no due date with one", timed, FilterOptions{NoDue: true}, false},
+		{"overdue", allDay, FilterOptions{Overdue: true}, true},
+		{"completed is not overdue", timed, FilterOptions{Overdue: true}, false},
+		{"no filters", undated, FilterOptions{}, true},
+	}
+
+	m := NewManagerWithBackend(storage.NewMemoryStorage())
+	m.SetLocation(berlin)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.matchesDates(tt.task, tt.filter); got != tt.want {
+				t.Errorf("matchesDates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
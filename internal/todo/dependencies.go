@@ -0,0 +1,223 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDependencyCycle is returned when a dependency would make a task wait on itself
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// DependsOnTask reports whether the task directly depends on id
+func (t *Task) DependsOnTask(id int) bool {
+	for _, dep := range t.DependsOn {
+		if dep == id {
+			return true
+		}
+	}
+	return false
+}
+
+// AddDependency makes the task wait for task id
+func (t *Task) AddDependency(id int) {
+	if t.DependsOnTask(id) {
+		return
+	}
+	t.DependsOn = append(t.DependsOn, id)
+	t.UpdatedAt = time.Now()
+}
+
+// RemoveDependency stops the task waiting for task id
+func (t *Task) RemoveDependency(id int) {
+	var deps []int
+	for _, dep := range t.DependsOn {
+		if dep != id {
+			deps = append(deps, dep)
+		}
+	}
+	if len(deps) != len(t.DependsOn) {
+		t.DependsOn = deps
+		t.UpdatedAt = time.Now()
+	}
+}
+
+// Blockers returns the pending tasks the task is waiting for. Dependencies on
+// tasks that no longer exist are ignored.
+func (m *Manager) Blockers(task *Task) []*Task {
+	var blockers []*Task
+	for _, id := range task.DependsOn {
+		dep, err := m.GetTask(id)
+		if err == nil && !dep.Completed {
+			blockers = append(blockers, dep)
+		}
+	}
+	return blockers
+}
+
+// IsBlocked reports whether the task is pending and waits for a task that is
+// not completed
+func (m *Manager) IsBlocked(task *Task) bool {
+	return !task.Completed && len(m.Blockers(task)) > 0
+}
+
+// dependencyPath returns the chain of dependencies leading from task from to
+// task to, or nil if from does not depend on to
+func (m *Manager) dependencyPath(from, to int) []int {
+	seen := make(map[int]bool)
+	var walk func(id int) []int
+	walk = func(id int) []int {
+		if id == to {
+			return []int{id}
+		}
+		if seen[id] {
+			return nil
+		}
+		seen[id] = true
+
+		task, err := m.GetTask(id)
+		if err != nil {
+			return nil
+		}
+		for _, dep := range task.DependsOn {
+			if path := walk(dep); path != nil {
+				return append([]int{id}, path...)
+			}
+		}
+		return nil
+	}
+	return walk(from)
+}
+
+// BlockTask makes task id wait until task onID is completed. It fails if
+// onID already depends on id, directly or through other tasks.
+func (m *Manager) BlockTask(id, onID int) (*Task, error) {
+	if id == onID {
+		return nil, invalidInputf("%w: task %d cannot depend on itself", ErrDependencyCycle, id)
+	}
+
+	var task *Task
+	err := m.update(func() error {
+		var err error
+		task, err = m.GetTask(id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := m.GetTask(onID); err != nil {
+			return fmt.Errorf("task %d: %w", onID, err)
+		}
+
+		if task.DependsOnTask(onID) {
+			return fmt.Errorf("task %d already depends on task %d", id, onID)
+		}
+
+		if path := m.dependencyPath(onID, id); path != nil {
+			return invalidInputf("%w: %s", ErrDependencyCycle, formatPath(append([]int{id}, path...)))
+		}
+
+		task.AddDependency(onID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// UnblockTask removes the dependency of task id on task onID, or all of its
+// dependencies if onID is zero
+func (m *Manager) UnblockTask(id, onID int) (*Task, error) {
+	var task *Task
+	err := m.update(func() error {
+		var err error
+		task, err = m.GetTask(id)
+		if err != nil {
+			return err
+		}
+
+		if len(task.DependsOn) == 0 {
+			return fmt.Errorf("task %d does not depend on any task", id)
+		}
+
+		if onID == 0 {
+			task.DependsOn = nil
+			task.UpdatedAt = time.Now()
+			return nil
+		}
+
+		if !task.DependsOnTask(onID) {
+			return fmt.Errorf("task %d does not depend on task %d", id, onID)
+		}
+		task.RemoveDependency(onID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// NextTasks returns the pending tasks that are not blocked, most important
+// first: by priority, then by due date (tasks without one last), then by ID
+func (m *Manager) NextTasks() []*Task {
+	var tasks []*Task
+	for _, task := range m.allTasks() {
+		if !task.Completed && !m.IsBlocked(task) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if priorityRank(a.Priority) != priorityRank(b.Priority) {
+			return priorityRank(a.Priority) > priorityRank(b.Priority)
+		}
+		if (a.DueDate == nil) != (b.DueDate == nil) {
+			return a.DueDate != nil
+		}
+		if a.DueDate != nil && !m.dueAt(a).Equal(m.dueAt(b)) {
+			return m.dueAt(a).Before(m.dueAt(b))
+		}
+		return a.ID < b.ID
+	})
+	return tasks
+}
+
+// blockedTaskIDs returns the IDs of the pending tasks that are currently blocked
+func (m *Manager) blockedTaskIDs() map[int]bool {
+	blocked := make(map[int]bool)
+	for _, task := range m.allTasks() {
+		if m.IsBlocked(task) {
+			blocked[task.ID] = true
+		}
+	}
+	return blocked
+}
+
+// priorityRank orders priorities from low (1) to high (3)
+func priorityRank(priority Priority) int {
+	switch priority {
+	case PriorityHigh:
+		return 3
+	case PriorityMedium:
+		return 2
+	case PriorityLow:
+		return 1
+	}
+	return 0
+}
+
+// formatPath formats a chain of task IDs as "1 → 2 → 3"
+func formatPath(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, " → ")
+}
@@ -0,0 +1,126 @@
+package todo
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"todo-cli/storage"
+)
+
+// newChainManager returns a manager with tasks 1 to 4, where task 1 waits
+// on task 2 and task 2 on task 3
+func newChainManager(t *testing.T) *Manager {
+	t.Helper()
+
+	m := NewManagerWithBackend(storage.NewMemoryStorage())
+	for _, title := range []string{"Deploy", "Test", "Build", "Announce"} {
+		if _, err := m.AddTask(title, PriorityMedium, nil); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+	for _, dep := range [][2]int{{1, 2}, {2, 3}} {
+		if _, err := m.BlockTask(dep[0], dep[1]); err != nil {
+			t.Fatalf("BlockTask(%d, %d) error = %v", dep[0], dep[1], err)
+		}
+	}
+	return m
+}
+
+func TestBlockTask(t *testing.T) {
+	tests := []struct {
+		name      string
+		id, onID  int
+		wantCycle string // path in the error; empty if the dependency is allowed
+	}{
+		{"self", 4, 4, "cannot depend on itself"},
+		{"direct cycle", 2, 1, "2 → 1 → 2"},
+		{"transitive cycle", 3, 1, "3 → 1 → 2 → 3"},
+		{"shortcut along the chain", 1, 3, ""},
+		{"new task at the end", 3, 4, ""},
+		{"new task at the start", 4, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newChainManager(t)
+
+			task, err := m.BlockTask(tt.id, tt.onID)
+			if tt.wantCycle == "" {
+				if err != nil {
+					t.Fatalf("BlockTask(%d, %d) error = %v", tt.id, tt.onID, err)
+				}
+				if !task.DependsOnTask(tt.onID) {
+					t.Errorf("task %d does not depend on task %d", tt.id, tt.onID)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrDependencyCycle) || !errors.Is(err, ErrInvalidInput) {
+				t.Fatalf("BlockTask(%d, %d) error = %v, want an invalid input dependency cycle", tt.id, tt.onID, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantCycle) {
+				t.Errorf("error %q does not show %q", err, tt.wantCycle)
+			}
+			if task, _ := m.GetTask(tt.id); task.DependsOnTask(tt.onID) {
+				t.Errorf("task %d depends on task %d after a refused block", tt.id, tt.onID)
+			}
+		})
+	}
+}
+
+func TestDependencyPath(t *testing.T) {
+	m := newChainManager(t)
+
+	tests := []struct {
+		from, to int
+		want     []int
+	}{
+		{1, 2, []int{1, 2}},
+		{1, 3, []int{1, 2, 3}},
+		{3, 1, nil},
+		{1, 4, nil},
+		{4, 4, []int{4}},
+	}
+
+	for _, tt := range tests {
+		if got := m.dependencyPath(tt.from, tt.to); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("dependencyPath(%d, %d) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestCompleteTaskUnblocks(t *testing.T) {
+	m := newChainManager(t)
+
+	// Task 4 waits on task 3 as well, task 1 still waits on task 2
+	if _, err := m.BlockTask(4, 3); err != nil {
+		t.Fatalf("BlockTask() error = %v", err)
+	}
+
+	tests := []struct {
+		complete int
+		want     []int
+	}{
+		{3, []int{2, 4}},
+		{4, nil},
+		{2, []int{1}},
+		{1, nil},
+	}
+
+	for _, tt := range tests {
+		result, err := m.CompleteTask(tt.complete)
+		if err != nil {
+			t.Fatalf("CompleteTask(%d) error = %v", tt.complete, err)
+		}
+
+		var got []int
+		for _, task := range result.Unblocked {
+			got = append(got, task.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("completing task %d unblocked %v, want %v", tt.complete, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package todo
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	locationsMu sync.Mutex
+	locations   = map[string]*time.Location{}
+)
+
+// loadLocation loads a time zone by name, caching the result
+func loadLocation(name string) (*time.Location, error) {
+	locationsMu.Lock()
+	defer locationsMu.Unlock()
+
+	if loc, ok := locations[name]; ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations[name] = loc
+	return loc, nil
+}
+
+// SetDueDate sets the task due date and records its time zone. An all-day
+// due date keeps only the calendar day of dueDate; a timed one is the exact
+// moment.
+func (t *Task) SetDueDate(dueDate time.Time, allDay bool) {
+	if allDay {
+		y, m, d := dueDate.Date()
+		dueDate = time.Date(y, m, d, 0, 0, 0, 0, dueDate.Location())
+	}
+	t.DueDate = &dueDate
+	t.DueAllDay = allDay
+	t.DueZone = dueDate.Location().String()
+	t.UpdatedAt = time.Now()
+}
+
+// dueLocation returns the time zone the due date was set in. Tasks saved
+// before zones were recorded use the zone the due date was read back in.
+func (t *Task) dueLocation() *time.Location {
+	if t.DueZone != "" {
+		if loc, err := loadLocation(t.DueZone); err == nil {
+			return loc
+		}
+	}
+	return t.DueDate.Location()
+}
+
+// localDue returns the due date in the time zone it was set in
+func (t *Task) localDue() time.Time {
+	return t.DueDate.In(t.dueLocation())
+}
+
+// DueIn returns the due date as seen in loc. An all-day due date is the
+// start of the same calendar day in loc, wherever it was set; a timed one is
+// the same moment. The task must have a due date.
+func (t *Task) DueIn(loc *time.Location) time.Time {
+	if !t.DueAllDay {
+		return t.DueDate.In(loc)
+	}
+	y, m, d := t.localDue().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
+
+// IsOverdueAt reports whether the task is overdue at now. An all-day task
+// is overdue once its day has ended in now's time zone, a timed task once
+// its due time has passed.
+func (t *Task) IsOverdueAt(now time.Time) bool {
+	if t.DueDate == nil || t.Completed {
+		return false
+	}
+	if t.DueAllDay {
+		return !now.Before(t.DueIn(now.Location()).AddDate(0, 0, 1))
+	}
+	return now.After(*t.DueDate)
+}
+
+// IsOverdue checks if the task is overdue in the local time zone
+func (t *Task) IsOverdue() bool {
+	return t.IsOverdueAt(time.Now())
+}
+
+// SetLocation sets the time zone used to decide which day it is, such as
+// when all-day tasks become overdue. The default is the local time zone.
+func (m *Manager) SetLocation(loc *time.Location) {
+	m.location = loc
+}
+
+// Location returns the manager's time zone
+func (m *Manager) Location() *time.Location {
+	if m.location == nil {
+		return time.Local
+	}
+	return m.location
+}
+
+// now returns the current time in the manager's time zone
+func (m *Manager) now() time.Time {
+	return time.Now().In(m.Location())
+}
+
+// dueAt returns when a task is due for ordering, an all-day task counting
+// from the start of its day in the manager's time zone
+func (m *Manager) dueAt(task *Task) time.Time {
+	return task.DueIn(m.Location())
+}
+
+// IsOverdue reports whether the task is overdue in the manager's time zone
+func (m *Manager) IsOverdue(task *Task) bool {
+	return task.IsOverdueAt(m.now())
+}
@@ -0,0 +1,48 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidInput matches errors caused by a request that cannot be
+	// carried out as given, such as an unknown priority or an empty title
+	ErrInvalidInput = errors.New("invalid input")
+
+	// ErrStorage matches errors reading or writing the task list
+	ErrStorage = errors.New("storage failure")
+)
+
+// kindError gives an error a kind that errors.Is matches, without changing
+// its message
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string        { return e.err.Error() }
+func (e *kindError) Unwrap() error        { return e.err }
+func (e *kindError) Is(target error) bool { return target == e.kind }
+
+// InvalidInput marks err as caused by invalid input. A nil err stays nil.
+func InvalidInput(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: ErrInvalidInput, err: err}
+}
+
+// invalidInputf formats an error caused by invalid input
+func invalidInputf(format string, args ...any) error {
+	return InvalidInput(fmt.Errorf(format, args...))
+}
+
+// StorageFailure marks err as a failure to read or write stored data. A nil
+// err stays nil.
+func StorageFailure(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: ErrStorage, err: err}
+}
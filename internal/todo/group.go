@@ -0,0 +1,141 @@
+package todo
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// GroupByNames lists the ways tasks can be grouped, for help and error
+// messages
+const GroupByNames = "priority, project, due-bucket, status"
+
+// TaskGroup is a headed section of a task list
+type TaskGroup struct {
+	Name  string
+	Tasks []*Task
+}
+
+// groupKey places a task in a group: order decides where the group comes,
+// and groups with the same order are sorted by name
+type groupKey struct {
+	order int
+	name  string
+}
+
+// ValidateGroupBy checks that tasks can be grouped by the given name
+func ValidateGroupBy(by string) error {
+	switch by {
+	case "priority", "project", "due-bucket", "status":
+		return nil
+	}
+	return invalidInputf("invalid group '%s'. Valid options: %s", by, GroupByNames)
+}
+
+// GroupTasks splits tasks into groups, keeping their order within each
+// group. Groups come in a fixed order: high priority first, overdue and
+// then the nearest due dates first, projects by name with tasks without a
+// project last. Empty groups are left out.
+func (m *Manager) GroupTasks(tasks []*Task, by string) ([]TaskGroup, error) {
+	if err := ValidateGroupBy(by); err != nil {
+		return nil, err
+	}
+
+	var key func(task *Task) groupKey
+	switch by {
+	case "priority":
+		key = func(task *Task) groupKey {
+			return groupKey{order: -priorityRank(task.Priority), name: capitalize(string(task.Priority))}
+		}
+	case "project":
+		key = func(task *Task) groupKey {
+			if task.Project == "" {
+				return groupKey{order: 1, name: "(no project)"}
+			}
+			return groupKey{name: task.Project}
+		}
+	case "due-bucket":
+		now := m.now()
+		key = func(task *Task) groupKey { return m.dueBucket(task, now) }
+	case "status":
+		key = m.statusGroup
+	}
+
+	var keys []groupKey
+	groups := make(map[groupKey]*TaskGroup)
+	for _, task := range tasks {
+		k := key(task)
+		group, ok := groups[k]
+		if !ok {
+			group = &TaskGroup{Name: k.name}
+			groups[k] = group
+			keys = append(keys, k)
+		}
+		group.Tasks = append(group.Tasks, task)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].order != keys[j].order {
+			return keys[i].order < keys[j].order
+		}
+		return keys[i].name < keys[j].name
+	})
+
+	result := make([]TaskGroup, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, *groups[k])
+	}
+	return result, nil
+}
+
+// dueBucket places a task by how soon it is due, with weeks starting on
+// Monday in the manager's time zone
+func (m *Manager) dueBucket(task *Task, now time.Time) groupKey {
+	if task.DueDate == nil {
+		return groupKey{order: 7, name: "No due date"}
+	}
+	if task.IsOverdueAt(now) {
+		return groupKey{order: 0, name: "Overdue"}
+	}
+
+	y, mo, d := now.Date()
+	today := time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+	nextWeek := today.AddDate(0, 0, 7-(int(today.Weekday())+6)%7)
+	due := m.dueAt(task)
+
+	switch {
+	case due.Before(today):
+		return groupKey{order: 1, name: "Earlier"}
+	case due.Before(today.AddDate(0, 0, 1)):
+		return groupKey{order: 2, name: "Today"}
+	case due.Before(today.AddDate(0, 0, 2)):
+		return groupKey{order: 3, name: "Tomorrow"}
+	case due.Before(nextWeek):
+		return groupKey{order: 4, name: "This week"}
+	case due.Before(nextWeek.AddDate(0, 0, 7)):
+		return groupKey{order: 5, name: "Next week"}
+	}
+	return groupKey{order: 6, name: "Later"}
+}
+
+// statusGroup places a task by its state: overdue, pending, blocked or
+// completed
+func (m *Manager) statusGroup(task *Task) groupKey {
+	switch {
+	case task.Completed:
+		return groupKey{order: 3, name: "Completed"}
+	case m.IsOverdue(task):
+		return groupKey{order: 0, name: "Overdue"}
+	case m.IsBlocked(task):
+		return groupKey{order: 2, name: "Blocked"}
+	}
+	return groupKey{order: 1, name: "Pending"}
+}
+
+// capitalize returns s with its first letter in upper case
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
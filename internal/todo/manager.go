@@ -3,16 +3,17 @@ package todo
 import (
 	"errors"
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
+	"todo-cli/internal/search"
 	"todo-cli/storage"
 )
 
 var (
 	ErrTaskNotFound = errors.New("task not found")
 	ErrInvalidID    = errors.New("invalid task ID")
+	ErrHasSubtasks  = errors.New("task has subtasks")
 )
 
 // FilterOptions defines options for filtering tasks
@@ -20,21 +21,81 @@ type FilterOptions struct {
 	ShowCompleted bool
 	ShowPending   bool
 	Priority      Priority
-	Search        string
-	SortBy        string // "id", "priority", "due", "created"
+	Search        string                // words and "phrases" to look for; see the search package
+	SearchRegex   bool                  // Search is a regular expression
+	Project       string                // tasks in this project or its sub-projects
+	Blocked       *bool                 // if set, only blocked (true) or unblocked (false) tasks
+	Tags          []string              // tasks must have all of these tags
+	ExcludeTags   []string              // tasks must have none of these tags
+	Due           TimeRange             // tasks due within this range
+	Created       TimeRange             // tasks created within this range
+	Completed     TimeRange             // tasks completed within this range
+	Overdue       bool                  // only overdue tasks
+	NoDue         bool                  // only tasks without a due date
+	Where         func(task *Task) bool // extra condition, such as a parsed query
+	SortBy        string                // sort order such as "-priority,due,id"; see ParseSort
+}
+
+// TaskUpdate describes a partial change to a task. Nil fields are left as they are.
+type TaskUpdate struct {
+	Title        *string
+	Priority     *Priority
+	DueDate      *time.Time
+	DueAllDay    bool // with DueDate: only the day of DueDate counts
+	ClearDueDate bool
+	Tags         *[]string
+	Project      *string // an empty name removes the project
+	ParentID     *int    // zero makes the task a top-level task
+	Recurrence   *string // RRULE or plain English; empty stops the task repeating
+	RecurFrom    *RecurFrom
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u TaskUpdate) IsEmpty() bool {
+	return u.Title == nil && u.Priority == nil && u.DueDate == nil && !u.ClearDueDate && u.Tags == nil && u.Project == nil && u.ParentID == nil &&
+		u.Recurrence == nil && u.RecurFrom == nil
+}
+
+// AddOptions holds the optional fields of a new task
+type AddOptions struct {
+	Priority   Priority
+	DueDate    *time.Time
+	DueAllDay  bool // only the day of DueDate counts, not its time
+	Tags       []string
+	Project    string
+	ParentID   int       // add as a subtask of this task; the project is inherited if not set
+	Recurrence string    // RRULE or plain English, such as "every 2nd tuesday"
+	RecurFrom  RecurFrom // what the next occurrence is counted from; due date by default
+}
+
+// CompleteResult describes the effects of completing a task
+type CompleteResult struct {
+	Task      *Task
+	Subtasks  []*Task // pending subtasks completed along with the task
+	Unblocked []*Task // pending tasks that no longer wait on anything
+	Next      *Task   // next occurrence of a recurring task, if one was created
 }
 
 // Manager handles all task operations
 type Manager struct {
-	storage *storage.FileStorage
-	tasks   []*Task
-	nextID  int
+	storage    storage.Backend
+	tasks      []*Task
+	loaded     bool  // tasks were read from storage; see allTasks
+	loadErr    error // why reading them on first use failed
+	nextID     int
+	autoBackup bool
+	location   *time.Location // time zone deciding which day it is; local if nil
 }
 
-// NewManager creates a new task manager
+// NewManager creates a new task manager backed by JSON file storage
 func NewManager(storagePath ...string) *Manager {
+	return NewManagerWithBackend(storage.NewFileStorage(storagePath...))
+}
+
+// NewManagerWithBackend creates a new task manager using the given storage backend
+func NewManagerWithBackend(backend storage.Backend) *Manager {
 	return &Manager{
-		storage: storage.NewFileStorage(storagePath...),
+		storage: backend,
 		tasks:   []*Task{},
 		nextID:  1,
 	}
@@ -44,72 +105,168 @@ func NewManager(storagePath ...string) *Manager {
 func (m *Manager) LoadTasks() error {
 	storageTasks, nextID, err := m.storage.LoadTasks()
 	if err != nil {
-		return fmt.Errorf("failed to load tasks: %w", err)
+		return StorageFailure(fmt.Errorf("failed to load tasks: %w", err))
 	}
-	
+
 	// Convert storage tasks to domain tasks
-	m.tasks = make([]*Task, len(storageTasks))
-	for i, st := range storageTasks {
-		m.tasks[i] = &Task{
-			ID:        st.ID,
-			Title:     st.Title,
-			Completed: st.Completed,
-			DueDate:   st.DueDate,
-			Priority:  Priority(st.Priority),
-			CreatedAt: st.CreatedAt,
-			UpdatedAt: st.UpdatedAt,
-		}
-	}
-	
+	m.tasks = fromStorageTasks(storageTasks)
+
 	m.nextID = nextID
+	m.loaded, m.loadErr = true, nil
 	return nil
 }
 
+// allTasks returns every task, reading them from storage the first time
+// they are needed unless LoadTasks was called. A list that cannot be read
+// counts as empty; GetTask and ListTasks report the error.
+func (m *Manager) allTasks() []*Task {
+	if !m.loaded {
+		m.loadErr = m.LoadTasks()
+		m.loaded = true
+	}
+	return m.tasks
+}
+
 // SaveTasks saves tasks to storage
 func (m *Manager) SaveTasks() error {
 	// Convert domain tasks to storage tasks
 	storageTasks := make([]*storage.Task, len(m.tasks))
 	for i, t := range m.tasks {
-		storageTasks[i] = &storage.Task{
-			ID:        t.ID,
-			Title:     t.Title,
-			Completed: t.Completed,
-			DueDate:   t.DueDate,
-			Priority:  storage.Priority(t.Priority),
-			CreatedAt: t.CreatedAt,
-			UpdatedAt: t.UpdatedAt,
-		}
+		storageTasks[i] = toStorageTask(t)
 	}
-	
+
 	if err := m.storage.SaveTasks(storageTasks, m.nextID); err != nil {
-		return fmt.Errorf("failed to save tasks: %w", err)
+		return StorageFailure(fmt.Errorf("failed to save tasks: %w", err))
 	}
 	return nil
 }
 
+// maxUpdateAttempts bounds how often a change is re-applied after losing a
+// race with another process
+const maxUpdateAttempts = 5
+
+// update runs one load-modify-save cycle. When the backend supports locking
+// the lock is held for the whole cycle, and tasks are reloaded first so the
+// change is applied on top of anything other processes saved in the meantime.
+// If the save still detects a concurrent change (for example where locking is
+// unreliable, such as on NFS), the tasks are reloaded and apply is run again.
+func (m *Manager) update(apply func() error) error {
+	return m.withLock(func() error {
+		for attempt := 1; ; attempt++ {
+			if err := m.LoadTasks(); err != nil {
+				return err
+			}
+
+			if err := apply(); err != nil {
+				return err
+			}
+
+			err := m.SaveTasks()
+			if errors.Is(err, storage.ErrConflict) && attempt < maxUpdateAttempts {
+				continue
+			}
+			return err
+		}
+	})
+}
+
+// withLock runs fn while holding the backend lock, if the backend has one
+func (m *Manager) withLock(fn func() error) error {
+	if locker, ok := m.storage.(storage.Locker); ok {
+		unlock, err := locker.Lock()
+		if err != nil {
+			return StorageFailure(err)
+		}
+		defer unlock()
+	}
+	return fn()
+}
+
+// SetAutoBackup enables taking a backup before destructive operations such as DeleteTask
+func (m *Manager) SetAutoBackup(enabled bool) {
+	m.autoBackup = enabled
+}
+
 // AddTask adds a new task
 func (m *Manager) AddTask(title string, priority Priority, dueDate *time.Time) (*Task, error) {
+	return m.AddTaskWithOptions(title, AddOptions{
+		Priority: priority,
+		DueDate:  dueDate,
+	})
+}
+
+// AddTaskWithOptions adds a new task with any of the optional fields set
+func (m *Manager) AddTaskWithOptions(title string, opts AddOptions) (*Task, error) {
 	if strings.TrimSpace(title) == "" {
-		return nil, errors.New("task title cannot be empty")
+		return nil, invalidInputf("task title cannot be empty")
 	}
 
-	task := NewTask(m.nextID, strings.TrimSpace(title))
-	
-	if priority != "" {
-		if !ValidatePriority(string(priority)) {
-			return nil, fmt.Errorf("invalid priority: %s (valid options: low, medium, high)", priority)
-		}
-		task.SetPriority(priority)
+	if opts.Priority != "" && !ValidatePriority(string(opts.Priority)) {
+		return nil, invalidInputf("invalid priority: %s (valid options: low, medium, high)", opts.Priority)
+	}
+
+	tags, err := normalizeTags(opts.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := NormalizeProject(opts.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrence, err := normalizeRecurrence(opts.Recurrence, opts.DueDate)
+	if err != nil {
+		return nil, err
 	}
-	
-	if dueDate != nil {
-		task.SetDueDate(*dueDate)
+
+	if !ValidateRecurFrom(string(opts.RecurFrom)) {
+		return nil, invalidInputf("invalid recurrence mode: %s (valid options: due, completion)", opts.RecurFrom)
 	}
 
-	m.tasks = append(m.tasks, task)
-	m.nextID++
+	var task *Task
+	err = m.update(func() error {
+		if opts.ParentID != 0 {
+			parent, err := m.checkParent(0, opts.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent.Completed {
+				return fmt.Errorf("parent task %d is completed", parent.ID)
+			}
+			if project == "" {
+				project = parent.Project
+			}
+		}
+
+		task = NewTask(m.nextID, strings.TrimSpace(title))
+		task.ParentID = opts.ParentID
+
+		if opts.Priority != "" {
+			task.SetPriority(opts.Priority)
+		}
+
+		if opts.DueDate != nil {
+			task.SetDueDate(*opts.DueDate, opts.DueAllDay)
+		}
+
+		if len(tags) > 0 {
+			task.SetTags(tags)
+		}
+
+		if project != "" {
+			task.SetProject(project)
+		}
+
+		if recurrence != "" {
+			task.SetRecurrence(recurrence, opts.RecurFrom)
+		}
 
-	if err := m.SaveTasks(); err != nil {
+		m.tasks = append(m.tasks, task)
+		m.nextID++
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -122,140 +279,465 @@ func (m *Manager) GetTask(id int) (*Task, error) {
 		return nil, ErrInvalidID
 	}
 
-	for _, task := range m.tasks {
+	for _, task := range m.allTasks() {
 		if task.ID == id {
 			return task, nil
 		}
 	}
-	
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+
 	return nil, ErrTaskNotFound
 }
 
-// CompleteTask marks a task as completed
-func (m *Manager) CompleteTask(id int) (*Task, error) {
-	task, err := m.GetTask(id)
+// CompleteTask marks a task as completed. Its pending subtasks are completed
+// with it, since a task is not done while any of its steps are open. The
+// result also lists the tasks that were waiting on it and are now unblocked.
+func (m *Manager) CompleteTask(id int) (*CompleteResult, error) {
+	var result *CompleteResult
+	err := m.update(func() error {
+		task, err := m.GetTask(id)
+		if err != nil {
+			return err
+		}
+
+		if task.Completed {
+			return fmt.Errorf("task %d is already completed", id)
+		}
+
+		result = &CompleteResult{Task: task}
+		blocked := m.blockedTaskIDs()
+
+		task.Complete()
+		for _, subtask := range m.GetDescendants(id) {
+			if !subtask.Completed {
+				subtask.Complete()
+				result.Subtasks = append(result.Subtasks, subtask)
+			}
+		}
+
+		for _, t := range m.tasks {
+			if blocked[t.ID] && !m.IsBlocked(t) {
+				result.Unblocked = append(result.Unblocked, t)
+			}
+		}
+
+		// Schedule the next occurrence of a recurring task, unless it was
+		// scheduled already and the task has been reopened since
+		if _, err := m.GetTask(task.NextOccurrence); task.IsRecurring() && err != nil {
+			next, err := m.nextOccurrence(task)
+			if err != nil {
+				return err
+			}
+			if next != nil {
+				m.tasks = append(m.tasks, next)
+				m.nextID++
+				task.NextOccurrence = next.ID
+				result.Next = next
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ReopenTask marks a completed task as pending again. Completed parent tasks
+// are reopened too, since they now have an open step.
+func (m *Manager) ReopenTask(id int) (*Task, error) {
+	var task *Task
+	err := m.update(func() error {
+		var err error
+		task, err = m.GetTask(id)
+		if err != nil {
+			return err
+		}
+
+		if !task.Completed {
+			return fmt.Errorf("task %d is not completed", id)
+		}
+
+		task.Reopen()
+		for parentID := task.ParentID; parentID != 0; {
+			parent, err := m.GetTask(parentID)
+			if err != nil || !parent.Completed {
+				break
+			}
+			parent.Reopen()
+			parentID = parent.ParentID
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if task.Completed {
-		return nil, fmt.Errorf("task %d is already completed", id)
+	return task, nil
+}
+
+// UpdateTask applies a partial update to a task, keeping its ID and creation date
+func (m *Manager) UpdateTask(id int, update TaskUpdate) (*Task, error) {
+	if update.IsEmpty() {
+		return nil, invalidInputf("nothing to update")
+	}
+
+	if update.Title != nil && strings.TrimSpace(*update.Title) == "" {
+		return nil, invalidInputf("task title cannot be empty")
+	}
+
+	if update.Priority != nil && !ValidatePriority(string(*update.Priority)) {
+		return nil, invalidInputf("invalid priority: %s (valid options: low, medium, high)", *update.Priority)
+	}
+
+	if update.DueDate != nil && update.ClearDueDate {
+		return nil, invalidInputf("cannot both set and clear the due date")
+	}
+
+	var tags []string
+	if update.Tags != nil {
+		var err error
+		tags, err = normalizeTags(*update.Tags)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var project string
+	if update.Project != nil {
+		var err error
+		project, err = NormalizeProject(*update.Project)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	task.Complete()
-	
-	if err := m.SaveTasks(); err != nil {
+	if update.RecurFrom != nil && !ValidateRecurFrom(string(*update.RecurFrom)) {
+		return nil, invalidInputf("invalid recurrence mode: %s (valid options: due, completion)", *update.RecurFrom)
+	}
+
+	var task *Task
+	err := m.update(func() error {
+		var err error
+		task, err = m.GetTask(id)
+		if err != nil {
+			return err
+		}
+
+		if update.ParentID != nil && *update.ParentID != 0 {
+			parent, err := m.checkParent(id, *update.ParentID)
+			if err != nil {
+				return err
+			}
+			if parent.Completed && !task.Completed {
+				return fmt.Errorf("parent task %d is completed", parent.ID)
+			}
+		}
+
+		if update.Title != nil {
+			task.SetTitle(strings.TrimSpace(*update.Title))
+		}
+
+		if update.Priority != nil {
+			task.SetPriority(*update.Priority)
+		}
+
+		if update.DueDate != nil {
+			task.SetDueDate(*update.DueDate, update.DueAllDay)
+		}
+
+		if update.ClearDueDate {
+			task.ClearDueDate()
+		}
+
+		if update.Tags != nil {
+			task.SetTags(tags)
+		}
+
+		if update.Project != nil {
+			task.SetProject(project)
+		}
+
+		if update.ParentID != nil {
+			task.SetParent(*update.ParentID)
+		}
+
+		if update.Recurrence != nil || update.RecurFrom != nil {
+			recurrence, from := task.Recurrence, task.RecurFrom
+			if update.Recurrence != nil {
+				var due *time.Time
+				if task.DueDate != nil {
+					localDue := task.localDue()
+					due = &localDue
+				}
+				recurrence, err = normalizeRecurrence(*update.Recurrence, due)
+				if err != nil {
+					return err
+				}
+			}
+			if update.RecurFrom != nil {
+				from = *update.RecurFrom
+			}
+			if recurrence == "" && update.Recurrence == nil {
+				return fmt.Errorf("task %d does not repeat", id)
+			}
+			task.SetRecurrence(recurrence, from)
+		}
+
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return task, nil
 }
 
-// DeleteTask removes a task
+// DeleteTask removes a task. Tasks that have subtasks are not deleted; the
+// error matches ErrHasSubtasks and DeleteTaskTree removes the whole tree.
 func (m *Manager) DeleteTask(id int) (*Task, error) {
+	deleted, err := m.deleteTasks(id, false)
+	if err != nil {
+		return nil, err
+	}
+	return deleted[0], nil
+}
+
+// DeleteTaskTree removes a task together with all of its subtasks. The
+// deleted task comes first in the result, followed by its subtasks.
+func (m *Manager) DeleteTaskTree(id int) ([]*Task, error) {
+	return m.deleteTasks(id, true)
+}
+
+// deleteTasks removes task id and, when withSubtasks is set, every task below it
+func (m *Manager) deleteTasks(id int, withSubtasks bool) ([]*Task, error) {
 	if id <= 0 {
 		return nil, ErrInvalidID
 	}
 
-	for i, task := range m.tasks {
-		if task.ID == id {
-			// Remove task from slice
-			deletedTask := *task
-			m.tasks = append(m.tasks[:i], m.tasks[i+1:]...)
-			
-			if err := m.SaveTasks(); err != nil {
-				return nil, err
+	// Back up once, not on every attempt of update, and only for a delete
+	// that is not going to be refused
+	if m.autoBackup {
+		if _, err := m.deletion(id, withSubtasks); err != nil {
+			return nil, err
+		}
+		if _, err := m.storage.BackupTasks(); err != nil {
+			return nil, StorageFailure(fmt.Errorf("failed to back up before delete: %w", err))
+		}
+	}
+
+	var deletedTasks []*Task
+	err := m.update(func() error {
+		remove, err := m.deletion(id, withSubtasks)
+		if err != nil {
+			return err
+		}
+
+		removed := make(map[int]bool, len(remove))
+		deletedTasks = deletedTasks[:0]
+		for _, t := range remove {
+			removed[t.ID] = true
+			deleted := *t
+			deletedTasks = append(deletedTasks, &deleted)
+		}
+
+		// Remove tasks from slice, and drop dependencies on them
+		kept := m.tasks[:0]
+		for _, t := range m.tasks {
+			if !removed[t.ID] {
+				for id := range removed {
+					t.RemoveDependency(id)
+				}
+				kept = append(kept, t)
 			}
-			
-			return &deletedTask, nil
 		}
+		m.tasks = kept
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	
-	return nil, ErrTaskNotFound
+
+	return deletedTasks, nil
+}
+
+// deletion returns task id followed by its subtasks when withSubtasks is
+// set, the tasks deleteTasks removes
+func (m *Manager) deletion(id int, withSubtasks bool) ([]*Task, error) {
+	task, err := m.GetTask(id)
+	if err != nil {
+		return nil, err
+	}
+
+	subtasks := m.GetDescendants(id)
+	if len(subtasks) > 0 && !withSubtasks {
+		return nil, fmt.Errorf("%w: task %d has %d subtask(s)", ErrHasSubtasks, id, len(subtasks))
+	}
+	return append([]*Task{task}, subtasks...), nil
 }
 
-// ListTasks returns filtered and sorted tasks
-func (m *Manager) ListTasks(filter FilterOptions) []*Task {
+// ListTasks returns filtered and sorted tasks. A search that cannot be
+// compiled is reported as invalid input.
+func (m *Manager) ListTasks(filter FilterOptions) ([]*Task, error) {
+	var candidates []*Task
+
+	var query *search.Query
+	if filter.Search != "" {
+		q, err := search.Compile(filter.Search, filter.SearchRegex)
+		if err != nil {
+			return nil, InvalidInput(err)
+		}
+		query = q
+	}
+
+	// Let backends that support it do the filtering natively, so the tasks
+	// that do not match are never read
+	if querier, ok := m.storage.(storage.Querier); ok {
+		storageTasks, err := querier.QueryTasks(toStorageQuery(filter))
+		if err == nil {
+			candidates = fromStorageTasks(storageTasks)
+		} else {
+			candidates = m.allTasks()
+		}
+	} else {
+		candidates = m.allTasks()
+	}
+
 	var filteredTasks []*Task
+	relevance := make(map[int]int)
+	for _, task := range candidates {
+		if !filter.matches(task) {
+			continue
+		}
 
-	for _, task := range m.tasks {
-		// Apply completion filter
-		if filter.ShowCompleted && !filter.ShowPending {
-			if !task.Completed {
-				continue
-			}
-		} else if filter.ShowPending && !filter.ShowCompleted {
-			if task.Completed {
-				continue
-			}
+		// Blocked state depends on other tasks, so it is checked here
+		if filter.Blocked != nil && m.IsBlocked(task) != *filter.Blocked {
+			continue
 		}
 
-		// Apply priority filter
-		if filter.Priority != "" {
-			if task.Priority != filter.Priority {
-				continue
-			}
+		// Dates depend on the manager's time zone, so they are checked here too
+		if !m.matchesDates(task, filter) {
+			continue
+		}
+
+		if filter.Where != nil && !filter.Where(task) {
+			continue
 		}
 
-		// Apply search filter
-		if filter.Search != "" {
-			searchLower := strings.ToLower(filter.Search)
-			titleLower := strings.ToLower(task.Title)
-			if !strings.Contains(titleLower, searchLower) {
+		// Search matches are scored for sorting by relevance
+		if query != nil {
+			score := query.Score(task.searchDocument())
+			if score == 0 {
 				continue
 			}
+			relevance[task.ID] = score
 		}
 
 		filteredTasks = append(filteredTasks, task)
 	}
 
+	if m.loadErr != nil {
+		return nil, m.loadErr
+	}
+
 	// Sort tasks
-	m.sortTasks(filteredTasks, filter.SortBy)
+	m.sortTasks(filteredTasks, filter.SortBy, relevance)
 
-	return filteredTasks
+	return filteredTasks, nil
 }
 
-// sortTasks sorts the task slice based on the specified criteria
-func (m *Manager) sortTasks(tasks []*Task, sortBy string) {
-	switch sortBy {
-	case "priority":
-		sort.Slice(tasks, func(i, j int) bool {
-			priorityOrder := map[Priority]int{
-				PriorityHigh:   3,
-				PriorityMedium: 2,
-				PriorityLow:    1,
-			}
-			return priorityOrder[tasks[i].Priority] > priorityOrder[tasks[j].Priority]
-		})
-	case "due":
-		sort.Slice(tasks, func(i, j int) bool {
-			// Tasks without due dates go to the end
-			if tasks[i].DueDate == nil && tasks[j].DueDate == nil {
-				return tasks[i].ID < tasks[j].ID
-			}
-			if tasks[i].DueDate == nil {
-				return false
-			}
-			if tasks[j].DueDate == nil {
-				return true
-			}
-			return tasks[i].DueDate.Before(*tasks[j].DueDate)
-		})
-	case "created":
-		sort.Slice(tasks, func(i, j int) bool {
-			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
-		})
-	default: // "id" or any other value
-		sort.Slice(tasks, func(i, j int) bool {
-			return tasks[i].ID < tasks[j].ID
-		})
+// matches reports whether a task passes the filter
+func (filter FilterOptions) matches(task *Task) bool {
+	// Apply completion filter
+	if filter.ShowCompleted && !filter.ShowPending {
+		if !task.Completed {
+			return false
+		}
+	} else if filter.ShowPending && !filter.ShowCompleted {
+		if task.Completed {
+			return false
+		}
+	}
+
+	// Apply priority filter
+	if filter.Priority != "" {
+		if task.Priority != filter.Priority {
+			return false
+		}
+	}
+
+	// Apply project filter
+	if filter.Project != "" {
+		project, _ := NormalizeProject(filter.Project)
+		if !task.InProject(project) {
+			return false
+		}
+	}
+
+	// Apply tag filters
+	for _, tag := range filter.Tags {
+		if !task.HasTag(tag) {
+			return false
+		}
+	}
+	for _, tag := range filter.ExcludeTags {
+		if task.HasTag(tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// toStorageQuery converts filter options to a query a backend can evaluate.
+// Searches are fuzzy and ranked, so they are always evaluated by ListTasks.
+func toStorageQuery(filter FilterOptions) storage.Query {
+	query := storage.Query{
+		Priority: storage.Priority(filter.Priority),
+	}
+
+	if project, err := NormalizeProject(filter.Project); err == nil {
+		query.Project = project
+	}
+
+	for _, tag := range filter.Tags {
+		query.Tags = append(query.Tags, NormalizeTag(tag))
+	}
+	for _, tag := range filter.ExcludeTags {
+		query.ExcludeTags = append(query.ExcludeTags, NormalizeTag(tag))
 	}
+
+	if filter.ShowCompleted && !filter.ShowPending {
+		completed := true
+		query.Completed = &completed
+	} else if filter.ShowPending && !filter.ShowCompleted {
+		completed := false
+		query.Completed = &completed
+	}
+
+	// An all-day due date counts from the start of its day in the manager's
+	// time zone, which can be up to a day from the stored time. The backend
+	// gets a range wide enough for that and matchesDates has the final say.
+	query.NoDue = filter.NoDue
+	query.Due = storage.TimeRange(filter.Due.widen(allDaySlack))
+	query.Created = storage.TimeRange(filter.Created)
+	query.CompletedAt = storage.TimeRange(filter.Completed)
+
+	return query
 }
 
 // GetStats returns statistics about tasks
 func (m *Manager) GetStats() map[string]int {
+	return taskStats(m.allTasks(), m.now())
+}
+
+// taskStats counts tasks by status and priority, with overdue tasks as of now
+func taskStats(tasks []*Task, now time.Time) map[string]int {
 	stats := map[string]int{
-		"total":     len(m.tasks),
+		"total":     len(tasks),
 		"completed": 0,
 		"pending":   0,
 		"overdue":   0,
@@ -264,12 +746,12 @@ func (m *Manager) GetStats() map[string]int {
 		"low":       0,
 	}
 
-	for _, task := range m.tasks {
+	for _, task := range tasks {
 		if task.Completed {
 			stats["completed"]++
 		} else {
 			stats["pending"]++
-			if task.IsOverdue() {
+			if task.IsOverdueAt(now) {
 				stats["overdue"]++
 			}
 		}
@@ -287,12 +769,93 @@ func (m *Manager) GetStats() map[string]int {
 	return stats
 }
 
-// GetStoragePath returns the current storage file path
+// GetStoragePath returns the current storage location
 func (m *Manager) GetStoragePath() string {
-	return m.storage.GetFilePath()
+	return m.storage.Describe()
 }
 
 // BackupTasks creates a backup of tasks
-func (m *Manager) BackupTasks() error {
-	return m.storage.BackupTasks()
-}
\ No newline at end of file
+func (m *Manager) BackupTasks() (*storage.Backup, error) {
+	backup, err := m.storage.BackupTasks()
+	return backup, StorageFailure(err)
+}
+
+// ListBackups returns the available backups, newest first
+func (m *Manager) ListBackups() ([]storage.Backup, error) {
+	backups, err := m.storage.ListBackups()
+	return backups, StorageFailure(err)
+}
+
+// RestoreBackup replaces all tasks with the backup identified by ref, an ID
+// from ListBackups or a timestamp
+func (m *Manager) RestoreBackup(ref string) (*storage.Backup, error) {
+	var backup *storage.Backup
+	err := m.withLock(func() error {
+		var err error
+		backup, err = m.storage.RestoreBackup(ref)
+		if err != nil {
+			return StorageFailure(err)
+		}
+		return m.LoadTasks()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backup, nil
+}
+
+// fromStorageTasks converts storage tasks to domain tasks
+func fromStorageTasks(storageTasks []*storage.Task) []*Task {
+	tasks := make([]*Task, len(storageTasks))
+	for i, st := range storageTasks {
+		tasks[i] = fromStorageTask(st)
+	}
+	return tasks
+}
+
+// fromStorageTask converts a storage task to a domain task
+func fromStorageTask(st *storage.Task) *Task {
+	return &Task{
+		ID:             st.ID,
+		Title:          st.Title,
+		Completed:      st.Completed,
+		DueDate:        st.DueDate,
+		DueAllDay:      st.DueAllDay,
+		DueZone:        st.DueZone,
+		Priority:       Priority(st.Priority),
+		CreatedAt:      st.CreatedAt,
+		UpdatedAt:      st.UpdatedAt,
+		CompletedAt:    st.CompletedAt,
+		Tags:           st.Tags,
+		Project:        st.Project,
+		ParentID:       st.ParentID,
+		DependsOn:      st.DependsOn,
+		Recurrence:     st.Recurrence,
+		RecurFrom:      RecurFrom(st.RecurFrom),
+		NextOccurrence: st.NextOccurrence,
+	}
+}
+
+// toStorageTask converts a domain task to a storage task
+func toStorageTask(t *Task) *storage.Task {
+	return &storage.Task{
+		ID:             t.ID,
+		Title:          t.Title,
+		Completed:      t.Completed,
+		DueDate:        t.DueDate,
+		DueAllDay:      t.DueAllDay,
+		DueZone:        t.DueZone,
+		Priority:       storage.Priority(t.Priority),
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+		CompletedAt:    t.CompletedAt,
+		Tags:           t.Tags,
+		Project:        t.Project,
+		ParentID:       t.ParentID,
+		DependsOn:      t.DependsOn,
+		Recurrence:     t.Recurrence,
+		RecurFrom:      string(t.RecurFrom),
+		NextOccurrence: t.NextOccurrence,
+	}
+}
@@ -0,0 +1,153 @@
+package todo
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+	_ "time/tzdata" // the zones below, even where the system has no zone database
+
+	"todo-cli/storage"
+)
+
+func TestListTasksQueriesSQLite(t *testing.T) {
+	honolulu, err := time.LoadLocation("Pacific/Honolulu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kiritimati, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	setup := NewManagerWithBackend(storage.NewSQLiteStorage(path))
+	setup.SetLocation(kiritimati)
+
+	// Due all day on March 10th, set in Honolulu 24 hours behind Kiritimati
+	due := time.Date(2026, time.March, 10, 0, 0, 0, 0, honolulu)
+	if _, err := setup.AddTaskWithOptions("Water plants", AddOptions{DueDate: &due, DueAllDay: true}); err != nil {
+		t.Fatalf("AddTaskWithOptions() error = %v", err)
+	}
+	if _, err := setup.AddTask("Buy milk", PriorityHigh, nil); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter FilterOptions
+		want   string
+	}{
+		{"priority", FilterOptions{Priority: PriorityHigh}, "Buy milk"},
+		{"no due date", FilterOptions{NoDue: true}, "Buy milk"},
+		{"all-day due date in the manager's time zone", FilterOptions{Due: TimeRange{
+			From: timePtr(time.Date(2026, time.March, 10, 0, 0, 0, 0, kiritimati)),
+			To:   timePtr(time.Date(2026, time.March, 11, 0, 0, 0, 0, kiritimati)),
+		}}, "Water plants"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManagerWithBackend(storage.NewSQLiteStorage(path))
+			m.SetLocation(kiritimati)
+
+			tasks := listTasks(t, m, tt.filter)
+			if len(tasks) != 1 || tasks[0].Title != tt.want {
+				t.Fatalf("ListTasks(%+v) = %v, want only %q", tt.filter, tasks, tt.want)
+			}
+
+			// The query was answered without reading the whole list
+			if m.loaded {
+				t.Errorf("ListTasks loaded every task")
+			}
+		})
+	}
+}
+
+func TestListTasksInvalidSearch(t *testing.T) {
+	m := NewManagerWithBackend(storage.NewMemoryStorage())
+	if _, err := m.AddTask("Deploy", PriorityMedium, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := m.ListTasks(FilterOptions{Search: "(deploy", SearchRegex: true})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("ListTasks() = %v, %v, want ErrInvalidInput", tasks, err)
+	}
+}
+
+// listTasks lists the tasks matching filter, failing the test on an error
+func listTasks(t *testing.T, m *Manager, filter FilterOptions) []*Task {
+	t.Helper()
+
+	tasks, err := m.ListTasks(filter)
+	if err != nil {
+		t.Fatalf("ListTasks(%+v) error = %v", filter, err)
+	}
+	return tasks
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// conflictingStorage is a MemoryStorage whose first saves report a
+// conflict, as if another process had saved in between, and which counts
+// the backups taken
+type conflictingStorage struct {
+	*storage.MemoryStorage
+	conflicts int
+	backups   int
+}
+
+func (cs *conflictingStorage) SaveTasks(tasks []*storage.Task, nextID int) error {
+	if cs.conflicts > 0 {
+		cs.conflicts--
+		return storage.ErrConflict
+	}
+	return cs.MemoryStorage.SaveTasks(tasks, nextID)
+}
+
+func (cs *conflictingStorage) BackupTasks() (*storage.Backup, error) {
+	cs.backups++
+	return cs.MemoryStorage.BackupTasks()
+}
+
+func TestDeleteTaskBacksUpOnce(t *testing.T) {
+	tests := []struct {
+		name        string
+		delete      int
+		wantErr     bool
+		wantBackups int
+	}{
+		{"deleted after conflicts", 1, false, 1},
+		{"missing task", 9, true, 0},
+		{"task with subtasks", 2, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &conflictingStorage{MemoryStorage: storage.NewMemoryStorage()}
+			m := NewManagerWithBackend(backend)
+			m.SetAutoBackup(true)
+
+			for _, task := range []struct {
+				title  string
+				parent int
+			}{{"Old", 0}, {"Parent", 0}, {"Child", 2}} {
+				if _, err := m.AddTaskWithOptions(task.title, AddOptions{ParentID: task.parent}); err != nil {
+					t.Fatalf("AddTaskWithOptions() error = %v", err)
+				}
+			}
+
+			backend.conflicts = 2
+			_, err := m.DeleteTask(tt.delete)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DeleteTask() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if backend.backups != tt.wantBackups {
+				t.Errorf("took %d backups, want %d", backend.backups, tt.wantBackups)
+			}
+		})
+	}
+}
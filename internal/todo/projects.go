@@ -0,0 +1,97 @@
+package todo
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// projectSeparator separates the levels of a project name, as in "work.backend.auth"
+const projectSeparator = "."
+
+// NormalizeProject converts a project name to its stored form: lower case,
+// with each dot-separated level trimmed. It returns an error for names with
+// empty levels or whitespace inside a level.
+func NormalizeProject(project string) (string, error) {
+	project = strings.ToLower(strings.TrimSpace(project))
+	if project == "" {
+		return "", nil
+	}
+
+	levels := strings.Split(project, projectSeparator)
+	for i, level := range levels {
+		level = strings.TrimSpace(level)
+		if level == "" || strings.ContainsAny(level, " \t\n") {
+			return "", invalidInputf("invalid project name: '%s'", project)
+		}
+		levels[i] = level
+	}
+	return strings.Join(levels, projectSeparator), nil
+}
+
+// InProject reports whether the task belongs to project or one of its
+// sub-projects, so "work" matches "work" and "work.backend" but not "workshop"
+func (t *Task) InProject(project string) bool {
+	return projectContains(project, t.Project)
+}
+
+// projectContains reports whether name is parent or below it
+func projectContains(parent, name string) bool {
+	return name == parent || strings.HasPrefix(name, parent+projectSeparator)
+}
+
+// SetProject sets the task project. An empty name removes it.
+func (t *Task) SetProject(project string) {
+	t.Project = project
+	t.UpdatedAt = time.Now()
+}
+
+// ProjectSummary holds the statistics of a project, including its sub-projects
+type ProjectSummary struct {
+	Project string
+	Depth   int // number of parent projects
+	Stats   map[string]int
+}
+
+// GetProjectStats returns the same statistics as GetStats, limited to the
+// tasks in project and its sub-projects
+func (m *Manager) GetProjectStats(project string) map[string]int {
+	var tasks []*Task
+	for _, task := range m.allTasks() {
+		if task.InProject(project) {
+			tasks = append(tasks, task)
+		}
+	}
+	return taskStats(tasks, m.now())
+}
+
+// GetProjectSummaries returns the statistics of every project in use and of
+// their parent projects, ordered so each project follows its parent
+func (m *Manager) GetProjectSummaries() []ProjectSummary {
+	names := make(map[string]bool)
+	for _, task := range m.allTasks() {
+		if task.Project == "" {
+			continue
+		}
+		levels := strings.Split(task.Project, projectSeparator)
+		for i := range levels {
+			names[strings.Join(levels[:i+1], projectSeparator)] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	summaries := make([]ProjectSummary, len(sorted))
+	for i, name := range sorted {
+		summaries[i] = ProjectSummary{
+			Project: name,
+			Depth:   strings.Count(name, projectSeparator),
+			Stats:   m.GetProjectStats(name),
+		}
+	}
+	return summaries
+}
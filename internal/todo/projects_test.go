@@ -0,0 +1,79 @@
+package todo
+
+import (
+	"errors"
+	"testing"
+
+	"todo-cli/storage"
+)
+
+func TestNormalizeProject(t *testing.T) {
+	tests := []struct {
+		project string
+		want    string
+		wantErr bool
+	}{
+		{"work", "work", false},
+		{"Work.API", "work.api", false},
+		{" work . backend ", "work.backend", false},
+		{"", "", false},
+		{"   ", "", false},
+		{"work..api", "", true},
+		{".work", "", true},
+		{"work.", "", true},
+		{"my work", "", true},
+		{"work.api v2", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeProject(tt.project)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("NormalizeProject(%q) error = %v, want invalid input", tt.project, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeProject(%q) = %q, %v, want %q", tt.project, got, err, tt.want)
+		}
+	}
+}
+
+func TestInProject(t *testing.T) {
+	tests := []struct {
+		task, project string
+		want          bool
+	}{
+		{"work", "work", true},
+		{"work.api", "work", true},
+		{"work.api.auth", "work", true},
+		{"work.api.auth", "work.api", true},
+		{"workshop", "work", false},
+		{"work", "work.api", false},
+		{"work.apis", "work.api", false},
+		{"home", "work", false},
+		{"", "work", false},
+	}
+
+	for _, tt := range tests {
+		task := &Task{Project: tt.task}
+		if got := task.InProject(tt.project); got != tt.want {
+			t.Errorf("task in %q InProject(%q) = %v, want %v", tt.task, tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestAddTaskRejectsInvalidProject(t *testing.T) {
+	m := NewManagerWithBackend(storage.NewMemoryStorage())
+
+	if _, err := m.AddTaskWithOptions("Plan", AddOptions{Project: "work..api"}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("AddTaskWithOptions() error = %v, want invalid input", err)
+	}
+	task, err := m.AddTaskWithOptions("Plan", AddOptions{Project: "Work.API"})
+	if err != nil {
+		t.Fatalf("AddTaskWithOptions() error = %v", err)
+	}
+	if task.Project != "work.api" {
+		t.Errorf("project = %q, want %q", task.Project, "work.api")
+	}
+}
@@ -0,0 +1,138 @@
+package todo
+
+import (
+	"fmt"
+	"time"
+
+	"todo-cli/internal/recur"
+)
+
+// RecurFrom selects what the next occurrence of a recurring task is counted from
+type RecurFrom string
+
+const (
+	// RecurFromDue schedules the next occurrence from the due date, keeping a
+	// fixed schedule however late the task is completed
+	RecurFromDue RecurFrom = "due"
+	// RecurFromCompletion schedules the next occurrence from the day the task
+	// was completed, as in "water the plants 3 days after last time"
+	RecurFromCompletion RecurFrom = "completion"
+)
+
+// ValidateRecurFrom checks if a recurrence mode is valid
+func ValidateRecurFrom(from string) bool {
+	switch RecurFrom(from) {
+	case "", RecurFromDue, RecurFromCompletion:
+		return true
+	}
+	return false
+}
+
+// IsRecurring reports whether the task repeats
+func (t *Task) IsRecurring() bool {
+	return t.Recurrence != ""
+}
+
+// DescribeRecurrence returns the recurrence rule in plain English, or an
+// empty string for tasks that do not repeat
+func (t *Task) DescribeRecurrence() string {
+	if !t.IsRecurring() {
+		return ""
+	}
+
+	rule, err := recur.ParseRRULE(t.Recurrence)
+	if err != nil {
+		return t.Recurrence
+	}
+
+	description := rule.Describe()
+	if t.RecurFrom == RecurFromCompletion {
+		description += " after completion"
+	}
+	return description
+}
+
+// SetRecurrence sets the recurrence rule (in RRULE form) and mode. An empty
+// rule makes the task a one-off task.
+func (t *Task) SetRecurrence(rule string, from RecurFrom) {
+	t.Recurrence = rule
+	t.RecurFrom = from
+	if rule == "" {
+		t.RecurFrom = ""
+	}
+	t.UpdatedAt = time.Now()
+}
+
+// normalizeRecurrence parses a rule written as an RRULE or in plain English
+// and returns its RRULE form. Monthly rules without a day are pinned to the
+// day of dueDate, so a task due on the 31st is not moved to the 28th for good
+// after February.
+func normalizeRecurrence(text string, dueDate *time.Time) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+
+	rule, err := recur.Parse(text)
+	if err != nil {
+		return "", InvalidInput(err)
+	}
+
+	if rule.Freq == recur.Monthly && len(rule.ByDay) == 0 && rule.ByMonthDay == 0 && dueDate != nil {
+		rule.ByMonthDay = dueDate.Day()
+	}
+	return rule.String(), nil
+}
+
+// nextOccurrence creates the task that follows a completed recurring task,
+// or returns nil if the rule has ended. It does not add it to the task list.
+func (m *Manager) nextOccurrence(task *Task) (*Task, error) {
+	rule, err := recur.ParseRRULE(task.Recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("task %d has an invalid recurrence rule: %w", task.ID, err)
+	}
+
+	// Count from the due date, or from the completion day at the due time.
+	// Both are taken in the zone the due date was set in, so the next one
+	// falls on the same wall-clock time across daylight saving changes.
+	loc := m.Location()
+	if task.DueDate != nil {
+		loc = task.dueLocation()
+	}
+
+	var from time.Time
+	if task.DueDate != nil && task.RecurFrom != RecurFromCompletion {
+		from = task.localDue()
+	} else {
+		completed := m.now()
+		if task.CompletedAt != nil {
+			completed = *task.CompletedAt
+		}
+		y, mo, d := completed.In(m.Location()).Date()
+		from = time.Date(y, mo, d, 0, 0, 0, 0, loc)
+		if task.DueDate != nil {
+			due := task.localDue()
+			from = time.Date(y, mo, d, due.Hour(), due.Minute(), due.Second(), 0, loc)
+		}
+	}
+
+	nextDue, rest, ok := rule.Next(from)
+	if !ok {
+		return nil, nil
+	}
+
+	next := NewTask(m.nextID, task.Title)
+	next.Priority = task.Priority
+	// A task without a due date repeats on whole days
+	next.SetDueDate(nextDue, task.DueAllDay || task.DueDate == nil)
+	next.Tags = append([]string(nil), task.Tags...)
+	next.Project = task.Project
+	next.Recurrence = rest.String()
+	next.RecurFrom = task.RecurFrom
+
+	// Stay under the parent while it is still open
+	if parent, err := m.GetTask(task.ParentID); err == nil && !parent.Completed {
+		next.ParentID = parent.ID
+	}
+
+	return next, nil
+}
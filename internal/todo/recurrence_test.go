@@ -0,0 +1,86 @@
+package todo
+
+import (
+	"testing"
+	"time"
+
+	"todo-cli/storage"
+)
+
+func TestCompleteUndatedRecurringTaskIsDueAllDay(t *testing.T) {
+	m := NewManagerWithBackend(storage.NewMemoryStorage())
+	m.SetLocation(time.UTC)
+
+	task, err := m.AddTaskWithOptions("Water plants", AddOptions{
+		Recurrence: "every 3 days",
+		RecurFrom:  RecurFromCompletion,
+	})
+	if err != nil {
+		t.Fatalf("AddTaskWithOptions() error = %v", err)
+	}
+
+	result, err := m.CompleteTask(task.ID)
+	if err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+
+	next := result.Next
+	if next == nil || next.DueDate == nil {
+		t.Fatalf("next occurrence = %+v, want one with a due date", next)
+	}
+	if !next.DueAllDay {
+		t.Errorf("next occurrence is due at %v, want an all-day due date", next.DueDate)
+	}
+
+	y, mo, d := result.Task.CompletedAt.In(time.UTC).AddDate(0, 0, 3).Date()
+	if got := next.DueIn(time.UTC); got.Year() != y || got.Month() != mo || got.Day() != d {
+		t.Errorf("next occurrence due %v, want %d-%02d-%02d", got, y, mo, d)
+	}
+}
+
+func TestRecompleteReopenedRecurringTask(t *testing.T) {
+	m := NewManagerWithBackend(storage.NewMemoryStorage())
+	m.SetLocation(time.UTC)
+
+	task, err := m.AddTaskWithOptions("Pay rent", AddOptions{Recurrence: "monthly"})
+	if err != nil {
+		t.Fatalf("AddTaskWithOptions() error = %v", err)
+	}
+
+	first, err := m.CompleteTask(task.ID)
+	if err != nil {
+		t.Fatalf("CompleteTask() error = %v", err)
+	}
+	if first.Next == nil {
+		t.Fatal("first completion scheduled no next occurrence")
+	}
+	if _, err := m.ReopenTask(task.ID); err != nil {
+		t.Fatalf("ReopenTask() error = %v", err)
+	}
+
+	again, err := m.CompleteTask(task.ID)
+	if err != nil {
+		t.Fatalf("CompleteTask() again error = %v", err)
+	}
+	if again.Next != nil {
+		t.Errorf("second completion scheduled task %d, want none", again.Next.ID)
+	}
+	if tasks := listTasks(t, m, FilterOptions{}); len(tasks) != 2 {
+		t.Errorf("got %d tasks, want the task and one next occurrence", len(tasks))
+	}
+
+	// Once the next occurrence is gone, completing schedules a new one
+	if _, err := m.DeleteTask(first.Next.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err := m.ReopenTask(task.ID); err != nil {
+		t.Fatalf("ReopenTask() error = %v", err)
+	}
+	third, err := m.CompleteTask(task.ID)
+	if err != nil {
+		t.Fatalf("CompleteTask() third error = %v", err)
+	}
+	if third.Next == nil || third.Next.ID == first.Next.ID {
+		t.Errorf("third completion scheduled %+v, want a new next occurrence", third.Next)
+	}
+}
@@ -0,0 +1,15 @@
+package todo
+
+import "todo-cli/internal/search"
+
+// ValidateSearch checks that search text, or a regular expression if regex
+// is set, can be used to filter tasks
+func ValidateSearch(text string, regex bool) error {
+	_, err := search.Compile(text, regex)
+	return InvalidInput(err)
+}
+
+// searchDocument returns the text of the task that searches look at
+func (t *Task) searchDocument() search.Document {
+	return search.Document{Title: t.Title, Tags: t.Tags}
+}
@@ -0,0 +1,148 @@
+package todo
+
+import (
+	"cmp"
+	"sort"
+	"strings"
+)
+
+// SortFieldNames lists the fields tasks can be sorted by, for help and
+// error messages
+const SortFieldNames = "id, priority, due, created, completed, project, title, relevance"
+
+// sortFields maps each sort field to whether it sorts descending by
+// default: priority from high to low and relevance from the best search
+// match down, everything else ascending
+var sortFields = map[string]bool{
+	"id":        false,
+	"priority":  true,
+	"due":       false,
+	"created":   false,
+	"completed": false,
+	"project":   false,
+	"title":     false,
+	"relevance": true,
+}
+
+// SortKey is one field of a sort order
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses a sort order such as "-priority,due,id". Each key is a
+// field name sorted in its natural direction (priority from high to low,
+// relevance from the best search match down, everything else ascending), or
+// with a - or + prefix descending or ascending. Tasks without a value for a
+// key, such as a due date, come last in either direction. An empty order
+// sorts by ID.
+func ParseSort(spec string) ([]SortKey, error) {
+	if strings.TrimSpace(spec) == "" {
+		return []SortKey{{Field: "id"}}, nil
+	}
+
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		field := strings.TrimLeft(part, "+-")
+
+		desc, ok := sortFields[field]
+		if !ok {
+			return nil, invalidInputf("invalid sort key '%s'. Valid keys: %s, each optionally prefixed with - or +", part, SortFieldNames)
+		}
+		if seen[field] {
+			return nil, invalidInputf("sort key '%s' given twice", field)
+		}
+		seen[field] = true
+
+		switch {
+		case strings.HasPrefix(part, "-"):
+			desc = true
+		case strings.HasPrefix(part, "+"):
+			desc = false
+		}
+		keys = append(keys, SortKey{Field: field, Desc: desc})
+	}
+	return keys, nil
+}
+
+// ValidateSort checks that a sort order can be parsed
+func ValidateSort(spec string) error {
+	_, err := ParseSort(spec)
+	return err
+}
+
+// sortTasks sorts tasks by a sort order such as "-priority,due", with
+// relevance holding the search score of each task ID. The sort is stable
+// and ties are broken by ID, so the same tasks always come out in the same
+// order. An order that cannot be parsed sorts by ID.
+func (m *Manager) sortTasks(tasks []*Task, sortBy string, relevance map[int]int) {
+	keys, err := ParseSort(sortBy)
+	if err != nil {
+		keys = nil
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return m.compareTasks(tasks[i], tasks[j], keys, relevance) < 0
+	})
+}
+
+// compareTasks compares two tasks by each key in turn, then by ID
+func (m *Manager) compareTasks(a, b *Task, keys []SortKey, relevance map[int]int) int {
+	for _, key := range keys {
+		if c := m.compareField(a, b, key, relevance); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// compareField compares two tasks by one sort key. Tasks without a value
+// for the field come after those with one, whatever the direction.
+func (m *Manager) compareField(a, b *Task, key SortKey, relevance map[int]int) int {
+	var c int
+	switch key.Field {
+	case "priority":
+		c = cmp.Compare(priorityRank(a.Priority), priorityRank(b.Priority))
+	case "due":
+		if a.DueDate == nil || b.DueDate == nil {
+			return compareMissing(a.DueDate == nil, b.DueDate == nil)
+		}
+		c = m.dueAt(a).Compare(m.dueAt(b))
+	case "created":
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	case "completed":
+		if a.CompletedAt == nil || b.CompletedAt == nil {
+			return compareMissing(a.CompletedAt == nil, b.CompletedAt == nil)
+		}
+		c = a.CompletedAt.Compare(*b.CompletedAt)
+	case "project":
+		if a.Project == "" || b.Project == "" {
+			return compareMissing(a.Project == "", b.Project == "")
+		}
+		c = strings.Compare(a.Project, b.Project)
+	case "title":
+		c = strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "relevance":
+		c = cmp.Compare(relevance[a.ID], relevance[b.ID])
+	default:
+		c = cmp.Compare(a.ID, b.ID)
+	}
+
+	if key.Desc {
+		return -c
+	}
+	return c
+}
+
+// compareMissing orders a task without a value after one with a value
+func compareMissing(aMissing, bMissing bool) int {
+	switch {
+	case aMissing == bMissing:
+		return 0
+	case aMissing:
+		return 1
+	}
+	return -1
+}
@@ -0,0 +1,149 @@
+package todo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"todo-cli/storage"
+)
+
+// newSortManager returns a manager holding five tasks that tie on some
+// sort keys and lack values for others
+func newSortManager(t *testing.T) *Manager {
+	t.Helper()
+
+	day := func(d int) *time.Time {
+		t := time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+	task := func(id int, title string, priority storage.Priority, due *time.Time, project string) *storage.Task {
+		return &storage.Task{ID: id, Title: title, Priority: priority, DueDate: due, Project: project,
+			CreatedAt: *day(id), UpdatedAt: *day(id)}
+	}
+
+	m := NewManagerWithBackend(storage.NewMemoryStorage(
+		task(1, "Beta", storage.PriorityHigh, day(5), "work"),
+		task(2, "alpha", storage.PriorityLow, nil, ""),
+		task(3, "Gamma", storage.PriorityHigh, nil, "home"),
+		task(4, "delta", storage.PriorityMedium, day(2), "work"),
+		task(5, "Epsilon", storage.PriorityHigh, day(5), ""),
+	))
+	m.SetLocation(time.UTC)
+	return m
+}
+
+// taskIDs returns the IDs of tasks in order
+func taskIDs(tasks []*Task) []int {
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []SortKey
+		wantErr bool
+	}{
+		{"", []SortKey{{Field: "id"}}, false},
+		{"priority", []SortKey{{Field: "priority", Desc: true}}, false},
+		{"+priority", []SortKey{{Field: "priority"}}, false},
+		{"-priority,due", []SortKey{{Field: "priority", Desc: true}, {Field: "due"}}, false},
+		{" Due , -ID ", []SortKey{{Field: "due"}, {Field: "id", Desc: true}}, false},
+		{"size", nil, true},
+		{"due,due", nil, true},
+		{"-due,+due", nil, true},
+		{"due,", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSort(tt.spec)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("ParseSort(%q) error = %v, want invalid input", tt.spec, err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSort(%q) = %v, %v, want %v", tt.spec, got, err, tt.want)
+		}
+	}
+}
+
+func TestListTasksSortOrder(t *testing.T) {
+	tests := []struct {
+		sortBy string
+		want   []int
+	}{
+		{"", []int{1, 2, 3, 4, 5}},
+		{"-priority,due", []int{1, 5, 3, 4, 2}},
+		{"due,-priority", []int{4, 1, 5, 3, 2}},
+		{"-due", []int{1, 5, 4, 2, 3}},
+		{"project,title", []int{3, 1, 4, 2, 5}},
+		{"-project", []int{1, 4, 3, 2, 5}},
+		{"title", []int{2, 1, 4, 5, 3}},
+		{"-created", []int{5, 4, 3, 2, 1}},
+		{"+priority,-id", []int{2, 4, 5, 3, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			m := newSortManager(t)
+
+			// Sorting the same tasks again gives the same order
+			for i := 0; i < 3; i++ {
+				if got := taskIDs(listTasks(t, m, FilterOptions{SortBy: tt.sortBy})); !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("sorted by %q = %v, want %v", tt.sortBy, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestGroupTasks(t *testing.T) {
+	tests := []struct {
+		by     string
+		sortBy string
+		want   map[string][]int
+		order  []string
+	}{
+		{"project", "-priority", map[string][]int{"home": {3}, "work": {1, 4}, "(no project)": {5, 2}},
+			[]string{"home", "work", "(no project)"}},
+		{"priority", "due", map[string][]int{"High": {1, 5, 3}, "Medium": {4}, "Low": {2}},
+			[]string{"High", "Medium", "Low"}},
+		{"due-bucket", "title", map[string][]int{"Overdue": {1, 4, 5}, "No due date": {2, 3}},
+			[]string{"Overdue", "No due date"}},
+		{"status", "id", map[string][]int{"Overdue": {1, 4, 5}, "Pending": {2, 3}},
+			[]string{"Overdue", "Pending"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			m := newSortManager(t)
+
+			groups, err := m.GroupTasks(listTasks(t, m, FilterOptions{SortBy: tt.sortBy}), tt.by)
+			if err != nil {
+				t.Fatalf("GroupTasks() error = %v", err)
+			}
+
+			var order []string
+			for _, group := range groups {
+				order = append(order, group.Name)
+				if got := taskIDs(group.Tasks); !reflect.DeepEqual(got, tt.want[group.Name]) {
+					t.Errorf("group %q = %v, want %v", group.Name, got, tt.want[group.Name])
+				}
+			}
+			if !reflect.DeepEqual(order, tt.order) {
+				t.Errorf("groups = %v, want %v", order, tt.order)
+			}
+		})
+	}
+
+	if _, err := newSortManager(t).GroupTasks(nil, "size"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("GroupTasks() by an unknown name error = %v, want invalid input", err)
+	}
+}
@@ -0,0 +1,133 @@
+package todo
+
+import (
+	"fmt"
+)
+
+// TreeItem is a task placed in a task tree
+type TreeItem struct {
+	Task  *Task
+	Depth int // 0 for top-level tasks
+}
+
+// GetSubtasks returns the direct subtasks of a task, ordered by ID
+func (m *Manager) GetSubtasks(id int) []*Task {
+	var subtasks []*Task
+	for _, task := range m.allTasks() {
+		if task.ParentID == id {
+			subtasks = append(subtasks, task)
+		}
+	}
+	m.sortTasks(subtasks, "id", nil)
+	return subtasks
+}
+
+// GetDescendants returns every task below id (subtasks, their subtasks and so
+// on), parents before their subtasks
+func (m *Manager) GetDescendants(id int) []*Task {
+	return m.descendants(id, map[int]bool{id: true})
+}
+
+// descendants collects the tasks below id, skipping any already seen so a
+// parent cycle in a hand-edited file cannot recurse forever
+func (m *Manager) descendants(id int, seen map[int]bool) []*Task {
+	var result []*Task
+	for _, child := range m.GetSubtasks(id) {
+		if seen[child.ID] {
+			continue
+		}
+		seen[child.ID] = true
+		result = append(result, child)
+		result = append(result, m.descendants(child.ID, seen)...)
+	}
+	return result
+}
+
+// SubtaskProgress returns how many of the tasks below id are completed, and
+// how many there are in total
+func (m *Manager) SubtaskProgress(id int) (done, total int) {
+	for _, task := range m.GetDescendants(id) {
+		total++
+		if task.Completed {
+			done++
+		}
+	}
+	return done, total
+}
+
+// isDescendant reports whether id is below ancestorID in the task tree
+func (m *Manager) isDescendant(id, ancestorID int) bool {
+	seen := make(map[int]bool)
+	for id != 0 && !seen[id] {
+		seen[id] = true
+		task, err := m.GetTask(id)
+		if err != nil {
+			return false
+		}
+		if task.ParentID == ancestorID {
+			return true
+		}
+		id = task.ParentID
+	}
+	return false
+}
+
+// checkParent verifies that parentID can be the parent of task id (zero for
+// a new task)
+func (m *Manager) checkParent(id, parentID int) (*Task, error) {
+	parent, err := m.GetTask(parentID)
+	if err != nil {
+		return nil, fmt.Errorf("parent task %d: %w", parentID, err)
+	}
+
+	if id != 0 && (parentID == id || m.isDescendant(parentID, id)) {
+		return nil, invalidInputf("task %d cannot be a subtask of itself or of its own subtasks", id)
+	}
+
+	return parent, nil
+}
+
+// Tree orders tasks as a tree: each task is followed by those of its
+// subtasks that are also in tasks. Tasks whose parent is not in tasks are
+// shown at the top level. The order of siblings is kept.
+func (m *Manager) Tree(tasks []*Task) []TreeItem {
+	included := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		included[task.ID] = true
+	}
+
+	children := make(map[int][]*Task)
+	var roots []*Task
+	for _, task := range tasks {
+		if task.ParentID != 0 && included[task.ParentID] && task.ParentID != task.ID {
+			children[task.ParentID] = append(children[task.ParentID], task)
+		} else {
+			roots = append(roots, task)
+		}
+	}
+
+	items := make([]TreeItem, 0, len(tasks))
+	visited := make(map[int]bool, len(tasks))
+	var walk func(task *Task, depth int)
+	walk = func(task *Task, depth int) {
+		if visited[task.ID] {
+			return
+		}
+		visited[task.ID] = true
+		items = append(items, TreeItem{Task: task, Depth: depth})
+		for _, child := range children[task.ID] {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, task := range roots {
+		walk(task, 0)
+	}
+
+	// Tasks caught in a parent cycle from a hand-edited file
+	for _, task := range tasks {
+		walk(task, 0)
+	}
+
+	return items
+}
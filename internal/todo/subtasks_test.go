@@ -0,0 +1,140 @@
+package todo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"todo-cli/storage"
+)
+
+// newTreeManager returns a manager with this tree of tasks:
+//
+//	1 Release
+//	├── 2 Write changelog
+//	│   └── 3 Collect merged PRs
+//	└── 4 Tag version
+//	5 Unrelated
+func newTreeManager(t *testing.T) *Manager {
+	t.Helper()
+
+	m := NewManagerWithBackend(storage.NewMemoryStorage())
+	tasks := []struct {
+		title  string
+		parent int
+	}{
+		{"Release", 0},
+		{"Write changelog", 1},
+		{"Collect merged PRs", 2},
+		{"Tag version", 1},
+		{"Unrelated", 0},
+	}
+	for _, task := range tasks {
+		if _, err := m.AddTaskWithOptions(task.title, AddOptions{ParentID: task.parent}); err != nil {
+			t.Fatalf("AddTaskWithOptions(%q) error = %v", task.title, err)
+		}
+	}
+	return m
+}
+
+// completedIDs returns the IDs of the completed tasks
+func completedIDs(t *testing.T, m *Manager) []int {
+	t.Helper()
+
+	var ids []int
+	for _, task := range listTasks(t, m, FilterOptions{ShowCompleted: true, SortBy: "id"}) {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func TestCompleteTaskCascadesToSubtasks(t *testing.T) {
+	tests := []struct {
+		complete     int
+		wantSubtasks []int
+		wantDone     []int
+	}{
+		{1, []int{2, 3, 4}, []int{1, 2, 3, 4}},
+		{2, []int{3}, []int{2, 3}},
+		{3, nil, []int{3}},
+	}
+
+	for _, tt := range tests {
+		m := newTreeManager(t)
+
+		result, err := m.CompleteTask(tt.complete)
+		if err != nil {
+			t.Fatalf("CompleteTask(%d) error = %v", tt.complete, err)
+		}
+
+		var subtasks []int
+		for _, task := range result.Subtasks {
+			subtasks = append(subtasks, task.ID)
+		}
+		if !reflect.DeepEqual(subtasks, tt.wantSubtasks) {
+			t.Errorf("completing task %d completed subtasks %v, want %v", tt.complete, subtasks, tt.wantSubtasks)
+		}
+		if got := completedIDs(t, m); !reflect.DeepEqual(got, tt.wantDone) {
+			t.Errorf("completing task %d left %v completed, want %v", tt.complete, got, tt.wantDone)
+		}
+	}
+}
+
+func TestDeleteTaskWithSubtasks(t *testing.T) {
+	m := newTreeManager(t)
+
+	if _, err := m.DeleteTask(2); !errors.Is(err, ErrHasSubtasks) {
+		t.Fatalf("DeleteTask() of a parent error = %v, want ErrHasSubtasks", err)
+	}
+	if tasks := listTasks(t, m, FilterOptions{}); len(tasks) != 5 {
+		t.Fatalf("refused delete left %d tasks, want 5", len(tasks))
+	}
+
+	// A leaf is deleted on its own
+	if _, err := m.DeleteTask(4); err != nil {
+		t.Fatalf("DeleteTask() of a leaf error = %v", err)
+	}
+
+	deleted, err := m.DeleteTaskTree(1)
+	if err != nil {
+		t.Fatalf("DeleteTaskTree() error = %v", err)
+	}
+	var ids []int
+	for _, task := range deleted {
+		ids = append(ids, task.ID)
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("DeleteTaskTree() deleted %v, want %v", ids, want)
+	}
+
+	tasks := listTasks(t, m, FilterOptions{})
+	if len(tasks) != 1 || tasks[0].ID != 5 {
+		t.Errorf("tasks left = %v, want only task 5", tasks)
+	}
+}
+
+func TestReopenTaskReopensAncestors(t *testing.T) {
+	tests := []struct {
+		reopen   int
+		wantDone []int
+	}{
+		{3, []int{4}},
+		{2, []int{3, 4}},
+		{4, []int{2, 3}},
+		{1, []int{2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		m := newTreeManager(t)
+		if _, err := m.CompleteTask(1); err != nil {
+			t.Fatalf("CompleteTask() error = %v", err)
+		}
+
+		if _, err := m.ReopenTask(tt.reopen); err != nil {
+			t.Fatalf("ReopenTask(%d) error = %v", tt.reopen, err)
+		}
+		if got := completedIDs(t, m); !reflect.DeepEqual(got, tt.wantDone) {
+			t.Errorf("reopening task %d left %v completed, want %v", tt.reopen, got, tt.wantDone)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package todo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NormalizeTag converts a tag to its stored form: lower case without a
+// leading '+'. It returns an empty string for tags that are not usable.
+func NormalizeTag(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	tag = strings.TrimPrefix(tag, "+")
+	if tag == "" || strings.ContainsAny(tag, " \t\n+") {
+		return ""
+	}
+	return tag
+}
+
+// ValidateTag checks if a tag is usable
+func ValidateTag(tag string) bool {
+	return NormalizeTag(tag) != ""
+}
+
+// normalizeTags normalizes and de-duplicates tags, rejecting invalid ones
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	for _, tag := range tags {
+		n := NormalizeTag(tag)
+		if n == "" {
+			return nil, invalidInputf("invalid tag: '%s'", tag)
+		}
+		normalized = appendTag(normalized, n)
+	}
+	return normalized, nil
+}
+
+// appendTag appends tag unless it is already present
+func appendTag(tags []string, tag string) []string {
+	for _, t := range tags {
+		if t == tag {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}
+
+// HasTag reports whether the task has the given tag
+func (t *Task) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, tt := range t.Tags {
+		if tt == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTags replaces the task tags
+func (t *Task) SetTags(tags []string) {
+	t.Tags = tags
+	t.UpdatedAt = time.Now()
+}
+
+// FormatTags returns the task tags as "+tag" words separated by spaces
+func (t *Task) FormatTags() string {
+	words := make([]string, len(t.Tags))
+	for i, tag := range t.Tags {
+		words[i] = "+" + tag
+	}
+	return strings.Join(words, " ")
+}
+
+// TagCount is the number of tasks using a tag
+type TagCount struct {
+	Tag     string
+	Total   int
+	Pending int
+}
+
+// GetTagCounts returns every tag in use with its task counts, sorted by name
+func (m *Manager) GetTagCounts() []TagCount {
+	counts := make(map[string]*TagCount)
+	for _, task := range m.allTasks() {
+		for _, tag := range task.Tags {
+			count, ok := counts[tag]
+			if !ok {
+				count = &TagCount{Tag: tag}
+				counts[tag] = count
+			}
+			count.Total++
+			if !task.Completed {
+				count.Pending++
+			}
+		}
+	}
+
+	result := make([]TagCount, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, *count)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
+
+// RenameTag renames a tag on every task. If a task already has the new tag
+// the two are merged. It returns the number of tasks changed.
+func (m *Manager) RenameTag(oldTag, newTag string) (int, error) {
+	return m.MergeTags([]string{oldTag}, newTag)
+}
+
+// MergeTags replaces each of the source tags with target on every task, in a
+// single save. It returns the number of tasks changed.
+func (m *Manager) MergeTags(sources []string, target string) (int, error) {
+	to := NormalizeTag(target)
+	if to == "" {
+		return 0, invalidInputf("invalid tag: '%s'", target)
+	}
+
+	from := make(map[string]bool)
+	for _, source := range sources {
+		tag := NormalizeTag(source)
+		if tag == "" {
+			return 0, invalidInputf("invalid tag: '%s'", source)
+		}
+		if tag != to {
+			from[tag] = true
+		}
+	}
+	if len(from) == 0 {
+		return 0, invalidInputf("tag '%s' is unchanged", to)
+	}
+
+	changed := 0
+	err := m.update(func() error {
+		changed = 0
+		for _, task := range m.tasks {
+			var tags []string
+			found := false
+			for _, tag := range task.Tags {
+				if from[tag] {
+					tag = to
+					found = true
+				}
+				tags = appendTag(tags, tag)
+			}
+			if !found {
+				continue
+			}
+
+			task.SetTags(tags)
+			changed++
+		}
+
+		if changed == 0 {
+			return fmt.Errorf("no tasks are tagged '%s'", strings.Join(sortedKeys(from), "', '"))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return changed, nil
+}
+
+// sortedKeys returns the keys of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
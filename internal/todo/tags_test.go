@@ -0,0 +1,134 @@
+package todo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"todo-cli/storage"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		tag, want string
+	}{
+		{"work", "work"},
+		{"+Work", "work"},
+		{"  OPS ", "ops"},
+		{"q2-review", "q2-review"},
+		{"+", ""},
+		{"", ""},
+		{"two words", ""},
+		{"a+b", ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeTag(tt.tag); got != tt.want {
+			t.Errorf("NormalizeTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		sources     []string
+		target      string
+		wantChanged int
+		wantTags    [][]string // tags of tasks 1 to 3 afterwards
+		wantErr     error
+	}{
+		{
+			name:        "rename",
+			sources:     []string{"home"},
+			target:      "house",
+			wantChanged: 2,
+			wantTags:    [][]string{{"work", "house"}, {"house", "errands"}, {"work"}},
+		},
+		{
+			name:        "sources and target are normalized",
+			sources:     []string{"+HOME", " Errands"},
+			target:      "+Chores",
+			wantChanged: 2,
+			wantTags:    [][]string{{"work", "chores"}, {"chores"}, {"work"}},
+		},
+		{
+			name:        "into an existing tag without duplicates",
+			sources:     []string{"home"},
+			target:      "work",
+			wantChanged: 2,
+			wantTags:    [][]string{{"work"}, {"work", "errands"}, {"work"}},
+		},
+		{
+			name:        "target among the sources is skipped",
+			sources:     []string{"work", "home"},
+			target:      "work",
+			wantChanged: 2,
+			wantTags:    [][]string{{"work"}, {"work", "errands"}, {"work"}},
+		},
+		{
+			name:    "invalid source",
+			sources: []string{"two words"},
+			target:  "work",
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "invalid target",
+			sources: []string{"home"},
+			target:  "+",
+			wantErr: ErrInvalidInput,
+		},
+		{
+			name:    "only the target",
+			sources: []string{"+Work"},
+			target:  "work",
+			wantErr: ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManagerWithBackend(storage.NewMemoryStorage())
+			for _, tags := range [][]string{{"work", "home"}, {"home", "errands"}, {"work"}} {
+				if _, err := m.AddTaskWithOptions("Task", AddOptions{Tags: tags}); err != nil {
+					t.Fatalf("AddTaskWithOptions() error = %v", err)
+				}
+			}
+
+			changed, err := m.MergeTags(tt.sources, tt.target)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("MergeTags() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeTags() error = %v", err)
+			}
+			if changed != tt.wantChanged {
+				t.Errorf("MergeTags() changed %d tasks, want %d", changed, tt.wantChanged)
+			}
+
+			for i, want := range tt.wantTags {
+				task, err := m.GetTask(i + 1)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(task.Tags, want) {
+					t.Errorf("task %d tags = %v, want %v", task.ID, task.Tags, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeTagsUnusedSource(t *testing.T) {
+	m := NewManagerWithBackend(storage.NewMemoryStorage())
+	if _, err := m.AddTaskWithOptions("Task", AddOptions{Tags: []string{"work"}}); err != nil {
+		t.Fatalf("AddTaskWithOptions() error = %v", err)
+	}
+
+	if _, err := m.MergeTags([]string{"home"}, "work"); err == nil {
+		t.Error("MergeTags() of an unused tag succeeded, want an error")
+	}
+}
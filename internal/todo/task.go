@@ -15,13 +15,26 @@ const (
 
 // Task represents a single todo item
 type Task struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Completed bool      `json:"completed"`
-	DueDate   *time.Time `json:"due_date,omitempty"`
-	Priority  Priority  `json:"priority"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Completed   bool       `json:"completed"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	DueAllDay   bool       `json:"due_all_day,omitempty"`
+	DueZone     string     `json:"due_zone,omitempty"` // time zone the due date was set in
+	Priority    Priority   `json:"priority"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Project     string     `json:"project,omitempty"`
+	ParentID    int        `json:"parent_id,omitempty"`
+	DependsOn   []int      `json:"depends_on,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty"` // RRULE, see package recur
+	RecurFrom   RecurFrom  `json:"recur_from,omitempty"`
+	// NextOccurrence is the ID of the task scheduled when this recurring
+	// task was completed, so completing it again after a reopen does not
+	// schedule a second one
+	NextOccurrence int `json:"next_occurrence,omitempty"`
 }
 
 // NewTask creates a new task with default values
@@ -39,7 +52,23 @@ func NewTask(id int, title string) *Task {
 
 // Complete marks a task as completed
 func (t *Task) Complete() {
+	now := time.Now()
 	t.Completed = true
+	t.CompletedAt = &now
+	t.UpdatedAt = now
+}
+
+// Reopen marks a completed task as pending again
+func (t *Task) Reopen() {
+	t.Completed = false
+	t.CompletedAt = nil
+	t.UpdatedAt = time.Now()
+}
+
+// SetParent makes the task a subtask of parentID, or a top-level task if
+// parentID is zero
+func (t *Task) SetParent(parentID int) {
+	t.ParentID = parentID
 	t.UpdatedAt = time.Now()
 }
 
@@ -49,18 +78,18 @@ func (t *Task) SetPriority(priority Priority) {
 	t.UpdatedAt = time.Now()
 }
 
-// SetDueDate sets the task due date
-func (t *Task) SetDueDate(dueDate time.Time) {
-	t.DueDate = &dueDate
+// SetTitle sets the task title
+func (t *Task) SetTitle(title string) {
+	t.Title = title
 	t.UpdatedAt = time.Now()
 }
 
-// IsOverdue checks if the task is overdue
-func (t *Task) IsOverdue() bool {
-	if t.DueDate == nil || t.Completed {
-		return false
-	}
-	return time.Now().After(*t.DueDate)
+// ClearDueDate removes the task due date
+func (t *Task) ClearDueDate() {
+	t.DueDate = nil
+	t.DueAllDay = false
+	t.DueZone = ""
+	t.UpdatedAt = time.Now()
 }
 
 // ValidatePriority checks if a priority string is valid
@@ -71,4 +100,4 @@ func ValidatePriority(priority string) bool {
 	default:
 		return false
 	}
-}
\ No newline at end of file
+}
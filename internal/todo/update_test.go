@@ -0,0 +1,174 @@
+package todo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"todo-cli/storage"
+)
+
+func TestUpdateTask(t *testing.T) {
+	due := time.Date(2026, time.March, 10, 15, 30, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+	prio := func(p Priority) *Priority { return &p }
+	tags := func(tags ...string) *[]string { return &tags }
+	parent := func(id int) *int { return &id }
+
+	tests := []struct {
+		name    string
+		update  TaskUpdate
+		check   func(t *testing.T, task *Task)
+		wantErr error
+	}{
+		{
+			name:   "title is trimmed",
+			update: TaskUpdate{Title: str("  Ship it  ")},
+			check: func(t *testing.T, task *Task) {
+				if task.Title != "Ship it" {
+					t.Errorf("title = %q, want %q", task.Title, "Ship it")
+				}
+			},
+		},
+		{
+			name:   "several fields at once",
+			update: TaskUpdate{Priority: prio(PriorityLow), Tags: tags("+Ops", "ops", "later"), Project: str("Work.API")},
+			check: func(t *testing.T, task *Task) {
+				if task.Priority != PriorityLow || task.Project != "work.api" || !reflect.DeepEqual(task.Tags, []string{"ops", "later"}) {
+					t.Errorf("task = %+v, want low priority, project work.api and tags [ops later]", task)
+				}
+			},
+		},
+		{
+			name:   "new due date",
+			update: TaskUpdate{DueDate: &due, DueAllDay: true},
+			check: func(t *testing.T, task *Task) {
+				if task.DueDate == nil || !task.DueAllDay || task.DueDate.Hour() != 0 || task.DueDate.Day() != 10 {
+					t.Errorf("due = %v, all day %v, want all day on the 10th", task.DueDate, task.DueAllDay)
+				}
+			},
+		},
+		{
+			name:   "clear due date",
+			update: TaskUpdate{ClearDueDate: true},
+			check: func(t *testing.T, task *Task) {
+				if task.DueDate != nil || task.DueAllDay {
+					t.Errorf("due = %v, all day %v, want none", task.DueDate, task.DueAllDay)
+				}
+			},
+		},
+		{
+			name:   "clear tags",
+			update: TaskUpdate{Tags: tags()},
+			check: func(t *testing.T, task *Task) {
+				if len(task.Tags) != 0 {
+					t.Errorf("tags = %v, want none", task.Tags)
+				}
+			},
+		},
+		{
+			name:   "clear project",
+			update: TaskUpdate{Project: str("")},
+			check: func(t *testing.T, task *Task) {
+				if task.Project != "" {
+					t.Errorf("project = %q, want none", task.Project)
+				}
+			},
+		},
+		{
+			name:   "stop repeating",
+			update: TaskUpdate{Recurrence: str("")},
+			check: func(t *testing.T, task *Task) {
+				if task.IsRecurring() {
+					t.Errorf("recurrence = %q, want none", task.Recurrence)
+				}
+			},
+		},
+		{
+			name:   "move to the top level",
+			update: TaskUpdate{ParentID: parent(0)},
+			check: func(t *testing.T, task *Task) {
+				if task.ParentID != 0 {
+					t.Errorf("parent = %d, want none", task.ParentID)
+				}
+			},
+		},
+		{name: "nothing to update", update: TaskUpdate{}, wantErr: ErrInvalidInput},
+		{name: "empty title", update: TaskUpdate{Title: str("   ")}, wantErr: ErrInvalidInput},
+		{name: "bad priority", update: TaskUpdate{Priority: prio("urgent")}, wantErr: ErrInvalidInput},
+		{name: "set and clear due date", update: TaskUpdate{DueDate: &due, ClearDueDate: true}, wantErr: ErrInvalidInput},
+		{name: "bad tag", update: TaskUpdate{Tags: tags("two words")}, wantErr: ErrInvalidInput},
+		{name: "bad project", update: TaskUpdate{Project: str("work..api")}, wantErr: ErrInvalidInput},
+		{name: "bad recurrence mode", update: TaskUpdate{RecurFrom: (*RecurFrom)(str("sometimes"))}, wantErr: ErrInvalidInput},
+		{name: "own subtask as parent", update: TaskUpdate{ParentID: parent(3)}, wantErr: ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManagerWithBackend(storage.NewMemoryStorage())
+			m.SetLocation(time.UTC)
+
+			parentTask, err := m.AddTask("Release", PriorityMedium, nil)
+			if err != nil {
+				t.Fatalf("AddTask() error = %v", err)
+			}
+			task, err := m.AddTaskWithOptions("Write notes", AddOptions{
+				Priority:   PriorityHigh,
+				DueDate:    &due,
+				Tags:       []string{"docs"},
+				Project:    "work",
+				ParentID:   parentTask.ID,
+				Recurrence: "weekly",
+			})
+			if err != nil {
+				t.Fatalf("AddTaskWithOptions() error = %v", err)
+			}
+			if _, err := m.AddTaskWithOptions("Proofread", AddOptions{ParentID: task.ID}); err != nil {
+				t.Fatalf("AddTaskWithOptions() error = %v", err)
+			}
+			before := *task
+
+			updated, err := m.UpdateTask(task.ID, tt.update)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("UpdateTask() error = %v, want %v", err, tt.wantErr)
+				}
+
+				// A refused update changes nothing
+				stored, _ := m.GetTask(task.ID)
+				if !reflect.DeepEqual(*stored, before) {
+					t.Errorf("task changed by a refused update:\n got %+v\nwant %+v", *stored, before)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateTask() error = %v", err)
+			}
+
+			if updated.ID != before.ID || !updated.CreatedAt.Equal(before.CreatedAt) {
+				t.Errorf("ID and creation = %d, %v, want %d, %v", updated.ID, updated.CreatedAt, before.ID, before.CreatedAt)
+			}
+			if updated.UpdatedAt.Before(before.UpdatedAt) {
+				t.Errorf("update time went back from %v to %v", before.UpdatedAt, updated.UpdatedAt)
+			}
+			tt.check(t, updated)
+
+			// The change was saved
+			stored, err := NewManagerWithBackend(m.storage).GetTask(task.ID)
+			if err != nil {
+				t.Fatalf("GetTask() error = %v", err)
+			}
+			tt.check(t, stored)
+		})
+	}
+}
+
+func TestUpdateMissingTask(t *testing.T) {
+	m := NewManagerWithBackend(storage.NewMemoryStorage())
+	title := "Anything"
+
+	if _, err := m.UpdateTask(7, TaskUpdate{Title: &title}); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("UpdateTask() error = %v, want ErrTaskNotFound", err)
+	}
+}
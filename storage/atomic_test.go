@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomicFailureKeepsOriginal(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, dir string) (path string, check func())
+	}{
+		{
+			name: "read-only directory",
+			setup: func(t *testing.T, dir string) (string, func()) {
+				if os.Geteuid() == 0 {
+					t.Skip("permissions are not enforced for root")
+				}
+				path := filepath.Join(dir, "tasks.json")
+				if err := os.WriteFile(path, []byte("original"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chmod(dir, 0555); err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { os.Chmod(dir, 0755) })
+
+				return path, func() {
+					if data, err := os.ReadFile(path); err != nil || string(data) != "original" {
+						t.Errorf("original file = %q, %v, want it unchanged", data, err)
+					}
+				}
+			},
+		},
+		{
+			name: "renaming onto a directory",
+			setup: func(t *testing.T, dir string) (string, func()) {
+				path := filepath.Join(dir, "tasks.json")
+				inside := filepath.Join(path, "keep")
+				if err := os.MkdirAll(path, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(inside, []byte("original"), 0644); err != nil {
+					t.Fatal(err)
+				}
+
+				return path, func() {
+					if data, err := os.ReadFile(inside); err != nil || string(data) != "original" {
+						t.Errorf("file in the directory = %q, %v, want it unchanged", data, err)
+					}
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path, check := tt.setup(t, dir)
+
+			if err := writeFileAtomic(path, []byte("replacement"), 0644); err == nil {
+				t.Fatal("writeFileAtomic() succeeded, want an error")
+			}
+			check()
+
+			// Only what was there before is left; the temporary file is gone
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 || entries[0].Name() != "tasks.json" {
+				var names []string
+				for _, entry := range entries {
+					names = append(names, entry.Name())
+				}
+				t.Errorf("directory holds %v, want only tasks.json", names)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Backend is the persistence layer used by the task manager.
+// FileStorage (JSON on disk) and MemoryStorage are the built-in
+// implementations; other backends only need to satisfy this interface.
+type Backend interface {
+	// LoadTasks returns all stored tasks and the next free task ID
+	LoadTasks() ([]*Task, int, error)
+
+	// SaveTasks replaces the stored tasks and the next free task ID
+	SaveTasks(tasks []*Task, nextID int) error
+
+	// BackupTasks creates a new backup of the stored tasks
+	BackupTasks() (*Backup, error)
+
+	// ListBackups returns the available backups, newest first
+	ListBackups() ([]Backup, error)
+
+	// RestoreBackup validates the backup identified by ref (an ID from
+	// ListBackups or a timestamp) and replaces the stored tasks with it
+	RestoreBackup(ref string) (*Backup, error)
+
+	// Describe returns a human-readable location of the stored tasks,
+	// such as a file path
+	Describe() string
+}
+
+// ErrConflict is returned by SaveTasks when the stored tasks were changed by
+// someone else since they were loaded. The caller should reload, re-apply its
+// change and save again.
+var ErrConflict = errors.New("task list was modified by another process")
+
+// ConflictError reports the revisions involved in a save conflict
+type ConflictError struct {
+	Expected int64 // revision the caller loaded
+	Found    int64 // revision currently stored
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v (loaded revision %d, found revision %d)", ErrConflict, e.Expected, e.Found)
+}
+
+// Is makes errors.Is(err, ErrConflict) match a ConflictError
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Query describes a task filter that a backend can evaluate natively
+type Query struct {
+	Completed   *bool     // nil matches both completed and pending tasks
+	Priority    Priority  // empty matches every priority
+	Project     string    // project name; also matches its sub-projects
+	Tags        []string  // tasks must have every one of these tags
+	ExcludeTags []string  // tasks must have none of these tags
+	Due         TimeRange // tasks with a stored due date within this range
+	NoDue       bool      // only tasks without a due date
+	Created     TimeRange // tasks created within this range
+	CompletedAt TimeRange // tasks completed within this range
+}
+
+// TimeRange selects times from From (inclusive) up to To (exclusive). A nil
+// end leaves that side open.
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// Querier is implemented by backends that can filter tasks themselves
+// instead of having the manager scan every task in memory
+type Querier interface {
+	QueryTasks(q Query) ([]*Task, error)
+}
+
+var (
+	_ Backend = (*FileStorage)(nil)
+	_ Backend = (*MemoryStorage)(nil)
+	_ Backend = (*SQLiteStorage)(nil)
+	_ Querier = (*SQLiteStorage)(nil)
+)
@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBackupRetention is how many backups are kept when none is configured
+const DefaultBackupRetention = 10
+
+// backupTimeFormat is the timestamp embedded in backup file names
+const backupTimeFormat = "20060102-150405.000"
+
+// ErrBackupNotFound is returned when no backup matches a restore reference
+var ErrBackupNotFound = errors.New("backup not found")
+
+// Backup describes a stored backup of the task list
+type Backup struct {
+	ID        int       `json:"id"` // 1 is the newest backup
+	Timestamp time.Time `json:"timestamp"`
+	Path      string    `json:"path,omitempty"`
+	Size      int64     `json:"size"`
+}
+
+// backupDir returns the directory holding backups of filePath
+func backupDir(filePath string) string {
+	return filepath.Join(filepath.Dir(filePath), "backups")
+}
+
+// backupPath returns the path of a new backup of filePath taken at t
+func backupPath(filePath string, t time.Time) string {
+	base := filepath.Base(filePath)
+	ext := filepath.Ext(base)
+	name := strings.TrimSuffix(base, ext) + "-" + t.UTC().Format(backupTimeFormat) + ext
+	return filepath.Join(backupDir(filePath), name)
+}
+
+// listBackupFiles returns the backups of filePath, newest first. The single
+// .backup file written by older versions is included as well.
+func listBackupFiles(filePath string) ([]Backup, error) {
+	base := filepath.Base(filePath)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext) + "-"
+
+	entries, err := os.ReadDir(backupDir(filePath))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var backups []Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		timestamp, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, Backup{
+			Timestamp: timestamp,
+			Path:      filepath.Join(backupDir(filePath), name),
+			Size:      info.Size(),
+		})
+	}
+
+	// Legacy single backup
+	legacyPath := filePath + ".backup"
+	if info, err := os.Stat(legacyPath); err == nil {
+		backups = append(backups, Backup{
+			Timestamp: info.ModTime().UTC(),
+			Path:      legacyPath,
+			Size:      info.Size(),
+		})
+	}
+
+	numberBackups(backups)
+	return backups, nil
+}
+
+// listedBackupFile returns the backup of filePath stored at path as
+// listBackupFiles describes it, so its ID and timestamp are the ones a
+// restore accepts. A legacy backup with a later time can come before it.
+func listedBackupFile(filePath, path string) (*Backup, error) {
+	backups, err := listBackupFiles(filePath)
+	if err != nil {
+		return nil, err
+	}
+	for i := range backups {
+		if backups[i].Path == path {
+			return &backups[i], nil
+		}
+	}
+	return nil, ErrBackupNotFound
+}
+
+// numberBackups sorts backups newest first and assigns their IDs
+func numberBackups(backups []Backup) {
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Timestamp.After(backups[j].Timestamp)
+	})
+	for i := range backups {
+		backups[i].ID = i + 1
+	}
+}
+
+// pruneBackupFiles removes the oldest timestamped backups of filePath so at
+// most retention remain. A retention of zero or less keeps everything. The
+// backup at keep, if any, is never removed and does not count towards the
+// retention, so a restore cannot delete the backup it is restoring.
+func pruneBackupFiles(filePath string, retention int, keep string) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	backups, err := listBackupFiles(filePath)
+	if err != nil {
+		return err
+	}
+
+	legacyPath := filePath + ".backup"
+	kept := 0
+	for _, backup := range backups {
+		if backup.Path == legacyPath || backup.Path == keep {
+			continue
+		}
+		kept++
+		if kept <= retention {
+			continue
+		}
+		if err := os.Remove(backup.Path); err != nil {
+			return fmt.Errorf("failed to remove old backup: %w", err)
+		}
+	}
+	return nil
+}
+
+// findBackup resolves a restore reference to a backup. The reference is
+// either a backup ID from ListBackups (up to three digits) or a timestamp such as
+// "20251005-143000", "2025-10-05 14:30" or an RFC 3339 time; a partial
+// timestamp picks the newest backup it matches.
+func findBackup(backups []Backup, ref string) (*Backup, error) {
+	ref = strings.TrimSpace(ref)
+
+	// Short numbers are IDs; anything else (such as "20251005") is a timestamp
+	if id, err := strconv.Atoi(ref); err == nil && len(ref) < 4 {
+		for i := range backups {
+			if backups[i].ID == id {
+				return &backups[i], nil
+			}
+		}
+		return nil, fmt.Errorf("%w: no backup with ID %d", ErrBackupNotFound, id)
+	}
+
+	if t, err := time.Parse(time.RFC3339, ref); err == nil {
+		ref = t.UTC().Format(backupTimeFormat)
+	} else if t, err := time.ParseInLocation("2006-01-02 15:04:05", ref, time.Local); err == nil {
+		ref = t.UTC().Format(backupTimeFormat)
+	} else if t, err := time.ParseInLocation("2006-01-02 15:04", ref, time.Local); err == nil {
+		ref = t.UTC().Format("20060102-1504")
+	}
+
+	// Backups are newest first, so the first match is the newest
+	for i := range backups {
+		if strings.HasPrefix(backups[i].Timestamp.UTC().Format(backupTimeFormat), ref) {
+			return &backups[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no backup matches '%s'", ErrBackupNotFound, ref)
+}
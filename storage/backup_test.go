@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// backupBackend is a backend whose backups are files with a retention count
+type backupBackend interface {
+	Backend
+	SetBackupRetention(retention int)
+}
+
+func TestRestoreKeepsSourceBackup(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(dir string) backupBackend
+	}{
+		{"json", func(dir string) backupBackend { return NewFileStorage(filepath.Join(dir, "tasks.json")) }},
+		{"sqlite", func(dir string) backupBackend { return NewSQLiteStorage(filepath.Join(dir, "tasks.db")) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := tt.open(t.TempDir())
+			if closer, ok := backend.(interface{ Close() error }); ok {
+				defer closer.Close()
+			}
+			backend.SetBackupRetention(2)
+
+			// Back up two versions of the list, so the restore source is the
+			// oldest backup and the first one pruning would remove. Each save
+			// loads first to pick up the revision.
+			now := time.Now()
+			for i, title := range []string{"original", "changed", "latest"} {
+				tasks := []*Task{{ID: 1, Title: title, Priority: PriorityMedium, CreatedAt: now, UpdatedAt: now}}
+				if _, _, err := backend.LoadTasks(); err != nil {
+					t.Fatalf("LoadTasks() error = %v", err)
+				}
+				if err := backend.SaveTasks(tasks, 2); err != nil {
+					t.Fatalf("SaveTasks() error = %v", err)
+				}
+				if i < 2 {
+					if _, err := backend.BackupTasks(); err != nil {
+						t.Fatalf("BackupTasks() error = %v", err)
+					}
+					// Backup names have millisecond timestamps
+					time.Sleep(5 * time.Millisecond)
+				}
+			}
+
+			restored, err := backend.RestoreBackup("2")
+			if err != nil {
+				t.Fatalf("RestoreBackup() error = %v", err)
+			}
+			if _, err := os.Stat(restored.Path); err != nil {
+				t.Errorf("restored backup was removed: %v", err)
+			}
+
+			tasks, _, err := backend.LoadTasks()
+			if err != nil {
+				t.Fatalf("LoadTasks() error = %v", err)
+			}
+			if len(tasks) != 1 || tasks[0].Title != "original" {
+				t.Errorf("restored tasks = %v, want the original task", tasks)
+			}
+		})
+	}
+}
+
+func TestBackupTasksMatchesListBackups(t *testing.T) {
+	tests := []struct {
+		name   string
+		open   func(dir string) Backend
+		legacy string // file name of a legacy backup to plant, if any
+	}{
+		{"json", func(dir string) Backend { return NewFileStorage(filepath.Join(dir, "tasks.json")) }, "tasks.json.backup"},
+		{"sqlite", func(dir string) Backend { return NewSQLiteStorage(filepath.Join(dir, "tasks.db")) }, "tasks.db.backup"},
+		{"memory", func(string) Backend { return NewMemoryStorage() }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			backend := tt.open(dir)
+			if closer, ok := backend.(interface{ Close() error }); ok {
+				defer closer.Close()
+			}
+
+			now := time.Now()
+			tasks := []*Task{{ID: 1, Title: "Keep me", Priority: PriorityMedium, CreatedAt: now, UpdatedAt: now}}
+			if _, _, err := backend.LoadTasks(); err != nil {
+				t.Fatalf("LoadTasks() error = %v", err)
+			}
+			if err := backend.SaveTasks(tasks, 2); err != nil {
+				t.Fatalf("SaveTasks() error = %v", err)
+			}
+
+			// A legacy backup dated in the future lists before new backups
+			if tt.legacy != "" {
+				legacy := filepath.Join(dir, tt.legacy)
+				if err := os.WriteFile(legacy, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+				future := now.Add(time.Hour)
+				if err := os.Chtimes(legacy, future, future); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i := 0; i < 3; i++ {
+				backup, err := backend.BackupTasks()
+				if err != nil {
+					t.Fatalf("BackupTasks() error = %v", err)
+				}
+				backups, err := backend.ListBackups()
+				if err != nil {
+					t.Fatalf("ListBackups() error = %v", err)
+				}
+				if backup.ID < 1 || backup.ID > len(backups) || backups[backup.ID-1] != *backup {
+					t.Fatalf("BackupTasks() = %+v, not in ListBackups() = %+v", backup, backups)
+				}
+				// Backup names have millisecond timestamps
+				time.Sleep(5 * time.Millisecond)
+			}
+		})
+	}
+}
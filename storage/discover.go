@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ProjectDirName is the directory that holds a project-local task list
+const ProjectDirName = ".todo"
+
+// ProjectFilePath returns the path of the project task list rooted at dir
+func ProjectFilePath(dir, fileName string) string {
+	return filepath.Join(dir, ProjectDirName, fileName)
+}
+
+// FindProjectFile looks for a project-local task list (.todo/<fileName>) in
+// start and each of its parents, the way git finds its repository. The
+// global list in the home directory is not treated as a project list.
+func FindProjectFile(start, fileName string) (string, bool) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", false
+	}
+
+	globalPath := defaultPath(fileName)
+
+	for {
+		candidate := ProjectFilePath(dir, fileName)
+		if candidate != globalPath {
+			if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+				return candidate, true
+			}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// DefaultFilePath returns the location of the global task list for fileName
+func DefaultFilePath(fileName string) string {
+	return defaultPath(fileName)
+}
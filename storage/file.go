@@ -8,7 +8,8 @@ import (
 	"time"
 )
 
-const defaultFileName = "tasks.json"
+// DefaultFileName is the name of the JSON tasks file
+const DefaultFileName = "tasks.json"
 
 // Priority represents task priority levels
 type Priority string
@@ -21,53 +22,88 @@ const (
 
 // Task represents a single todo item for storage
 type Task struct {
-	ID        int       `json:"id"`
-	Title     string    `json:"title"`
-	Completed bool      `json:"completed"`
-	DueDate   *time.Time `json:"due_date,omitempty"`
-	Priority  Priority  `json:"priority"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          int        `json:"id"`
+	Title       string     `json:"title"`
+	Completed   bool       `json:"completed"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	DueAllDay   bool       `json:"due_all_day,omitempty"`
+	DueZone     string     `json:"due_zone,omitempty"`
+	Priority    Priority   `json:"priority"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	Project     string     `json:"project,omitempty"`
+	ParentID    int        `json:"parent_id,omitempty"`
+	DependsOn   []int      `json:"depends_on,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	RecurFrom   string     `json:"recur_from,omitempty"`
+	// NextOccurrence is the ID of the task scheduled when this one was completed
+	NextOccurrence int `json:"next_occurrence,omitempty"`
 }
 
 // FileStorage handles saving and loading tasks to/from JSON files
 type FileStorage struct {
-	filePath string
+	filePath        string
+	lockTimeout     time.Duration
+	backupRetention int
+	revision        int64 // revision of the file as of the last load or save
 }
 
 // TaskList represents the structure stored in JSON
 type TaskList struct {
-	Tasks      []*Task `json:"tasks"`
-	NextID     int     `json:"next_id"`
-	LastUpdate string  `json:"last_update"`
+	SchemaVersion int       `json:"schema_version"`
+	Tasks         []*Task   `json:"tasks"`
+	NextID        int       `json:"next_id"`
+	Revision      int64     `json:"revision"`
+	LastModified  time.Time `json:"last_modified"`
+	ModifiedBy    string    `json:"modified_by,omitempty"`
 }
 
 // NewFileStorage creates a new file storage instance
 func NewFileStorage(customPath ...string) *FileStorage {
-	var filePath string
-	
+	filePath := defaultPath(DefaultFileName)
 	if len(customPath) > 0 && customPath[0] != "" {
 		filePath = customPath[0]
-	} else {
-		// Default to user's home directory
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			// Fallback to current directory
-			filePath = defaultFileName
-		} else {
-			filePath = filepath.Join(homeDir, ".todo", defaultFileName)
-		}
 	}
-	
+
 	return &FileStorage{
-		filePath: filePath,
+		filePath:        filePath,
+		lockTimeout:     DefaultLockTimeout,
+		backupRetention: DefaultBackupRetention,
+	}
+}
+
+// defaultPath returns the default location of a storage file in ~/.todo
+func defaultPath(fileName string) string {
+	// Default to user's home directory
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		// Fallback to current directory
+		return fileName
 	}
+	return filepath.Join(homeDir, ".todo", fileName)
 }
 
 // ensureDir creates the directory for the storage file if it doesn't exist
 func (fs *FileStorage) ensureDir() error {
-	dir := filepath.Dir(fs.filePath)
-	return os.MkdirAll(dir, 0755)
+	return ensureParentDir(fs.filePath)
+}
+
+// ensureParentDir creates the parent directory of filePath if it doesn't exist
+func ensureParentDir(filePath string) error {
+	return os.MkdirAll(filepath.Dir(filePath), 0755)
+}
+
+// SetLockTimeout sets how long Lock waits for another process to release the file
+func (fs *FileStorage) SetLockTimeout(timeout time.Duration) {
+	fs.lockTimeout = timeout
+}
+
+// Lock takes an exclusive advisory lock on the tasks file (via a .lock file
+// next to it) so concurrent invocations cannot overwrite each other's changes
+func (fs *FileStorage) Lock() (func() error, error) {
+	return lockFile(fs.filePath+".lock", fs.lockTimeout)
 }
 
 // LoadTasks loads tasks from the JSON file
@@ -77,27 +113,66 @@ func (fs *FileStorage) LoadTasks() ([]*Task, int, error) {
 		return nil, 1, fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Check if file exists
-	if _, err := os.Stat(fs.filePath); os.IsNotExist(err) {
-		// Return empty list if file doesn't exist
-		return []*Task{}, 1, nil
+	taskList, err := fs.readTaskList()
+	if err != nil {
+		return nil, 1, err
 	}
 
+	fs.revision = taskList.Revision
+	return taskList.Tasks, taskList.NextID, nil
+}
+
+// readTaskList reads and parses the tasks file. A missing or empty file is
+// returned as an empty list at revision 0.
+func (fs *FileStorage) readTaskList() (*TaskList, error) {
 	// Read file
 	data, err := os.ReadFile(fs.filePath)
+	if os.IsNotExist(err) {
+		// Return empty list if file doesn't exist
+		return &TaskList{Tasks: []*Task{}, NextID: 1}, nil
+	}
 	if err != nil {
-		return nil, 1, fmt.Errorf("failed to read file: %w", err)
+		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	// Handle empty file
 	if len(data) == 0 {
-		return []*Task{}, 1, nil
+		return &TaskList{Tasks: []*Task{}, NextID: 1}, nil
+	}
+
+	taskList, version, err := parseTaskList(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the original of files upgraded from an older layout. The
+	// upgraded layout is written on the next save.
+	if version != CurrentSchemaVersion {
+		if err := keepPreMigrationCopy(fs.filePath, version, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return taskList, nil
+}
+
+// parseTaskList decodes a tasks document, upgrading it to the current schema.
+// It also returns the schema version the document was written with.
+func parseTaskList(data []byte) (*TaskList, int, error) {
+	// Upgrade documents written by older versions
+	data, version, err := migrateTaskList(data)
+	if err != nil {
+		return nil, version, err
 	}
 
 	// Parse JSON
 	var taskList TaskList
 	if err := json.Unmarshal(data, &taskList); err != nil {
-		return nil, 1, fmt.Errorf("failed to parse JSON: %w", err)
+		return nil, version, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	if taskList.Tasks == nil {
+		taskList.Tasks = []*Task{}
 	}
 
 	// Ensure we have a valid NextID
@@ -111,21 +186,56 @@ func (fs *FileStorage) LoadTasks() ([]*Task, int, error) {
 		}
 	}
 
-	return taskList.Tasks, taskList.NextID, nil
+	return &taskList, version, nil
+}
+
+// validateTaskList checks that a decoded task list is safe to use
+func validateTaskList(taskList *TaskList) error {
+	seen := make(map[int]bool)
+	for _, task := range taskList.Tasks {
+		if task == nil {
+			return fmt.Errorf("task list contains an empty entry")
+		}
+		if task.ID <= 0 {
+			return fmt.Errorf("task has invalid ID %d", task.ID)
+		}
+		if seen[task.ID] {
+			return fmt.Errorf("task ID %d appears more than once", task.ID)
+		}
+		seen[task.ID] = true
+		if task.Title == "" {
+			return fmt.Errorf("task %d has no title", task.ID)
+		}
+	}
+	return nil
 }
 
-// SaveTasks saves tasks to the JSON file
+// SaveTasks saves tasks to the JSON file. It refuses to overwrite the file if
+// another process saved a newer revision since it was loaded, returning an
+// error that matches ErrConflict.
 func (fs *FileStorage) SaveTasks(tasks []*Task, nextID int) error {
 	// Ensure directory exists
 	if err := fs.ensureDir(); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
+	// Check that nobody saved since we loaded
+	current, err := fs.readTaskList()
+	if err != nil {
+		return err
+	}
+	if current.Revision != fs.revision {
+		return &ConflictError{Expected: fs.revision, Found: current.Revision}
+	}
+
 	// Create task list structure
 	taskList := TaskList{
-		Tasks:      tasks,
-		NextID:     nextID,
-		LastUpdate: fmt.Sprintf("%v", os.Getenv("USER")),
+		SchemaVersion: CurrentSchemaVersion,
+		Tasks:         tasks,
+		NextID:        nextID,
+		Revision:      fs.revision + 1,
+		LastModified:  time.Now(),
+		ModifiedBy:    os.Getenv("USER"),
 	}
 
 	// Marshal to JSON with indentation
@@ -134,11 +244,12 @@ func (fs *FileStorage) SaveTasks(tasks []*Task, nextID int) error {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(fs.filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	// Write to file atomically so a crash never truncates the existing list
+	if err := writeFileAtomic(fs.filePath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write file (existing file left unchanged): %w", err)
 	}
 
+	fs.revision = taskList.Revision
 	return nil
 }
 
@@ -147,28 +258,105 @@ func (fs *FileStorage) GetFilePath() string {
 	return fs.filePath
 }
 
+// Describe returns the storage file path
+func (fs *FileStorage) Describe() string {
+	return fs.filePath
+}
+
 // FileExists checks if the storage file exists
 func (fs *FileStorage) FileExists() bool {
 	_, err := os.Stat(fs.filePath)
 	return !os.IsNotExist(err)
 }
 
-// BackupTasks creates a backup of the current tasks file
-func (fs *FileStorage) BackupTasks() error {
+// SetBackupRetention sets how many backups BackupTasks keeps. Zero or less
+// keeps every backup.
+func (fs *FileStorage) SetBackupRetention(retention int) {
+	fs.backupRetention = retention
+}
+
+// BackupTasks creates a timestamped backup of the current tasks file and
+// removes the oldest backups beyond the retention count
+func (fs *FileStorage) BackupTasks() (*Backup, error) {
+	return fs.backupTasks("")
+}
+
+// backupTasks creates a backup, never pruning the backup at keep
+func (fs *FileStorage) backupTasks(keep string) (*Backup, error) {
 	if !fs.FileExists() {
-		return fmt.Errorf("no tasks file to backup")
+		return nil, fmt.Errorf("no tasks file to backup")
 	}
 
-	backupPath := fs.filePath + ".backup"
-	
 	data, err := os.ReadFile(fs.filePath)
 	if err != nil {
-		return fmt.Errorf("failed to read tasks file: %w", err)
+		return nil, fmt.Errorf("failed to read tasks file: %w", err)
 	}
 
-	if err := os.WriteFile(backupPath, data, 0644); err != nil {
-		return fmt.Errorf("failed to create backup: %w", err)
+	now := time.Now()
+	path := backupPath(fs.filePath, now)
+	if err := ensureParentDir(path); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
 	}
 
-	return nil
-}
\ No newline at end of file
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	if err := pruneBackupFiles(fs.filePath, fs.backupRetention, keep); err != nil {
+		return nil, err
+	}
+
+	return listedBackupFile(fs.filePath, path)
+}
+
+// ListBackups returns the available backups, newest first
+func (fs *FileStorage) ListBackups() ([]Backup, error) {
+	return listBackupFiles(fs.filePath)
+}
+
+// RestoreBackup replaces the tasks file with the backup identified by ref
+// (an ID from ListBackups or a timestamp). The backup is fully validated
+// before anything is changed, and the current file is backed up first.
+func (fs *FileStorage) RestoreBackup(ref string) (*Backup, error) {
+	backups, err := fs.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := findBackup(backups, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	restored, _, err := parseTaskList(data)
+	if err != nil {
+		return nil, fmt.Errorf("backup %s is not usable: %w", backup.Path, err)
+	}
+	if err := validateTaskList(restored); err != nil {
+		return nil, fmt.Errorf("backup %s is not usable: %w", backup.Path, err)
+	}
+
+	// Keep what is being replaced
+	if fs.FileExists() {
+		if _, err := fs.backupTasks(backup.Path); err != nil {
+			return nil, fmt.Errorf("failed to back up current tasks: %w", err)
+		}
+	}
+
+	// Save on top of the current revision so other processes notice the change
+	current, err := fs.readTaskList()
+	if err != nil {
+		return nil, err
+	}
+	fs.revision = current.Revision
+
+	if err := fs.SaveTasks(restored.Tasks, restored.NextID); err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
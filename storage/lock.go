@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLockTimeout is how long a command waits for another process to
+// release the task list before giving up
+const DefaultLockTimeout = 5 * time.Second
+
+// lockRetryInterval is how often a held lock is retried while waiting
+const lockRetryInterval = 50 * time.Millisecond
+
+// ErrLocked is returned when the task list is locked by another process
+var ErrLocked = errors.New("task list is locked")
+
+// LockError reports which process holds the lock on a task list
+type LockError struct {
+	Path string
+	PID  int
+}
+
+func (e *LockError) Error() string {
+	if e.PID > 0 {
+		return fmt.Sprintf("list is locked by PID %d (%s)", e.PID, e.Path)
+	}
+	return fmt.Sprintf("list is locked by another process (%s)", e.Path)
+}
+
+// Is makes errors.Is(err, ErrLocked) match a LockError
+func (e *LockError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// Locker is implemented by backends that can serialize a load-modify-save
+// cycle across processes
+type Locker interface {
+	// Lock blocks until the lock is acquired or the backend's timeout
+	// expires, and returns a function that releases it
+	Lock() (unlock func() error, err error)
+}
+
+var _ Locker = (*FileStorage)(nil)
+
+// lockFile takes an exclusive advisory lock on path, waiting up to timeout.
+// The holder's PID is written to the file so waiting processes can report it.
+func lockFile(path string, timeout time.Duration) (func() error, error) {
+	if err := ensureParentDir(path); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			break
+		}
+
+		if !time.Now().Before(deadline) {
+			pid := readLockPID(f)
+			f.Close()
+			return nil, &LockError{Path: path, PID: pid}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	// Record the owner for anyone else waiting on the lock
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+
+	unlock := func() error {
+		// Clear the PID before releasing so a stale value is never reported
+		f.Truncate(0)
+		err := unlockFile(f)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	return unlock, nil
+}
+
+// readLockPID returns the PID recorded in a lock file, or 0 if unknown
+func readLockPID(f *os.File) int {
+	buf := make([]byte, 32)
+	n, _ := f.ReadAt(buf, 0)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(buf[:n])))
+	if err != nil {
+		return 0
+	}
+	return pid
+}
@@ -0,0 +1,16 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package storage
+
+import "os"
+
+// tryLockFile always succeeds: advisory locking is not implemented on this
+// platform, so concurrent invocations are not serialized
+func tryLockFile(f *os.File) (bool, error) {
+	return true, nil
+}
+
+// unlockFile is a no-op on platforms without advisory locking
+func unlockFile(f *os.File) error {
+	return nil
+}
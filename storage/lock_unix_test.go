@@ -0,0 +1,50 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockFileTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json.lock")
+
+	unlock, err := lockFile(path, time.Second)
+	if err != nil {
+		t.Fatalf("lockFile() error = %v", err)
+	}
+
+	// A second open file description conflicts, even within this process
+	start := time.Now()
+	_, err = lockFile(path, 100*time.Millisecond)
+	if waited := time.Since(start); waited < 100*time.Millisecond {
+		t.Errorf("gave up after %v, want at least the timeout", waited)
+	}
+
+	var lockErr *LockError
+	if !errors.As(err, &lockErr) || !errors.Is(err, ErrLocked) {
+		t.Fatalf("lockFile() on a held lock error = %v, want a LockError", err)
+	}
+	if lockErr.PID != os.Getpid() || lockErr.Path != path {
+		t.Errorf("LockError = %+v, want PID %d and path %s", lockErr, os.Getpid(), path)
+	}
+	if want := fmt.Sprintf("locked by PID %d", os.Getpid()); !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+
+	// Once released the lock is free again
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock() error = %v", err)
+	}
+	unlock, err = lockFile(path, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("lockFile() after unlock error = %v", err)
+	}
+	unlock()
+}
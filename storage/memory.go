@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MemoryStorage keeps tasks in memory only. It is meant for tests and for
+// embedding the task manager in programs that handle persistence themselves.
+type MemoryStorage struct {
+	mu              sync.Mutex
+	tasks           []*Task
+	nextID          int
+	backups         []memoryBackup
+	backupRetention int
+}
+
+// memoryBackup is a snapshot held by MemoryStorage
+type memoryBackup struct {
+	timestamp time.Time
+	tasks     []*Task
+	nextID    int
+}
+
+// NewMemoryStorage creates a new in-memory storage seeded with the given tasks
+func NewMemoryStorage(tasks ...*Task) *MemoryStorage {
+	nextID := 1
+	for _, task := range tasks {
+		if task.ID >= nextID {
+			nextID = task.ID + 1
+		}
+	}
+
+	return &MemoryStorage{
+		tasks:           cloneTasks(tasks),
+		nextID:          nextID,
+		backupRetention: DefaultBackupRetention,
+	}
+}
+
+// LoadTasks returns a copy of the tasks held in memory
+func (ms *MemoryStorage) LoadTasks() ([]*Task, int, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return cloneTasks(ms.tasks), ms.nextID, nil
+}
+
+// SaveTasks replaces the tasks held in memory with a copy of tasks
+func (ms *MemoryStorage) SaveTasks(tasks []*Task, nextID int) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.tasks = cloneTasks(tasks)
+	ms.nextID = nextID
+	return nil
+}
+
+// SetBackupRetention sets how many snapshots BackupTasks keeps. Zero or less
+// keeps every snapshot.
+func (ms *MemoryStorage) SetBackupRetention(retention int) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.backupRetention = retention
+}
+
+// BackupTasks keeps a snapshot of the current tasks in memory
+func (ms *MemoryStorage) BackupTasks() (*Backup, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if len(ms.tasks) == 0 {
+		return nil, fmt.Errorf("no tasks to backup")
+	}
+
+	now := time.Now()
+	ms.backups = append(ms.backups, memoryBackup{
+		timestamp: now,
+		tasks:     cloneTasks(ms.tasks),
+		nextID:    ms.nextID,
+	})
+
+	if ms.backupRetention > 0 && len(ms.backups) > ms.backupRetention {
+		ms.backups = ms.backups[len(ms.backups)-ms.backupRetention:]
+	}
+
+	// Snapshots taken at the same time are listed in the order taken, so
+	// this one is the last with its time
+	backups := ms.listBackups()
+	for i := len(backups) - 1; i >= 0; i-- {
+		if backups[i].Timestamp.Equal(now) {
+			return &backups[i], nil
+		}
+	}
+	return nil, ErrBackupNotFound
+}
+
+// ListBackups returns the snapshots held in memory, newest first
+func (ms *MemoryStorage) ListBackups() ([]Backup, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return ms.listBackups(), nil
+}
+
+// RestoreBackup replaces the tasks held in memory with a snapshot
+func (ms *MemoryStorage) RestoreBackup(ref string) (*Backup, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	backup, err := findBackup(ms.listBackups(), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range ms.backups {
+		if snapshot.timestamp.Equal(backup.Timestamp) {
+			ms.tasks = cloneTasks(snapshot.tasks)
+			ms.nextID = snapshot.nextID
+			return backup, nil
+		}
+	}
+	return nil, ErrBackupNotFound
+}
+
+// listBackups describes the snapshots; the caller must hold ms.mu
+func (ms *MemoryStorage) listBackups() []Backup {
+	backups := make([]Backup, len(ms.backups))
+	for i, snapshot := range ms.backups {
+		backups[i] = Backup{Timestamp: snapshot.timestamp}
+	}
+	numberBackups(backups)
+	return backups
+}
+
+// Describe returns a description of the in-memory storage
+func (ms *MemoryStorage) Describe() string {
+	return "memory"
+}
+
+// cloneTasks returns a deep copy of tasks so callers cannot mutate stored data
+func cloneTasks(tasks []*Task) []*Task {
+	cloned := make([]*Task, len(tasks))
+	for i, task := range tasks {
+		t := *task
+		if task.DueDate != nil {
+			due := *task.DueDate
+			t.DueDate = &due
+		}
+		if task.CompletedAt != nil {
+			completedAt := *task.CompletedAt
+			t.CompletedAt = &completedAt
+		}
+		if task.Tags != nil {
+			t.Tags = append([]string(nil), task.Tags...)
+		}
+		if task.DependsOn != nil {
+			t.DependsOn = append([]int(nil), task.DependsOn...)
+		}
+		cloned[i] = &t
+	}
+	return cloned
+}
@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// CurrentSchemaVersion is the newest tasks file layout this binary can read
+// and the layout it writes. Bump it together with a registered migration
+// whenever the stored format changes.
+const CurrentSchemaVersion = 9
+
+// ErrSchemaTooNew is returned when a tasks file was written by a newer
+// version of todo than this one
+var ErrSchemaTooNew = errors.New("tasks file was written by a newer version of todo")
+
+// Migration upgrades a decoded tasks document from one schema version to the next
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc map[string]any) error
+}
+
+// migrations holds the registered upgrade steps, keyed by the version they
+// upgrade from
+var migrations = map[int]Migration{}
+
+// registerMigration adds a migration to the registry
+func registerMigration(m Migration) {
+	if _, exists := migrations[m.From]; exists {
+		panic(fmt.Sprintf("storage: duplicate migration from schema version %d", m.From))
+	}
+	migrations[m.From] = m
+}
+
+func init() {
+	// Version 0 is every file written before schema_version existed
+	registerMigration(Migration{
+		From:        0,
+		Description: "add schema version, replace last_update with modified_by",
+		Apply: func(doc map[string]any) error {
+			// last_update only ever held $USER
+			if user, ok := doc["last_update"]; ok {
+				if _, exists := doc["modified_by"]; !exists {
+					doc["modified_by"] = user
+				}
+				delete(doc, "last_update")
+			}
+
+			tasks, _ := doc["tasks"].([]any)
+			for _, t := range tasks {
+				task, ok := t.(map[string]any)
+				if !ok {
+					continue
+				}
+				if p, _ := task["priority"].(string); p == "" {
+					task["priority"] = string(PriorityMedium)
+				}
+			}
+			return nil
+		},
+	})
+
+	registerMigration(Migration{
+		From:        1,
+		Description: "add completed_at, estimated from updated_at",
+		Apply: func(doc map[string]any) error {
+			tasks, _ := doc["tasks"].([]any)
+			for _, t := range tasks {
+				task, ok := t.(map[string]any)
+				if !ok {
+					continue
+				}
+				if completed, _ := task["completed"].(bool); !completed {
+					continue
+				}
+				if _, exists := task["completed_at"]; !exists {
+					task["completed_at"] = task["updated_at"]
+				}
+			}
+			return nil
+		},
+	})
+
+	// Tags are optional, so older files need no changes; the version bump
+	// keeps older binaries from dropping tags they do not know about
+	registerMigration(Migration{
+		From:        2,
+		Description: "add tags",
+		Apply: func(doc map[string]any) error {
+			return nil
+		},
+	})
+
+	registerMigration(Migration{
+		From:        3,
+		Description: "add project",
+		Apply: func(doc map[string]any) error {
+			return nil
+		},
+	})
+
+	registerMigration(Migration{
+		From:        4,
+		Description: "add parent_id",
+		Apply: func(doc map[string]any) error {
+			return nil
+		},
+	})
+
+	registerMigration(Migration{
+		From:        5,
+		Description: "add depends_on",
+		Apply: func(doc map[string]any) error {
+			return nil
+		},
+	})
+
+	registerMigration(Migration{
+		From:        6,
+		Description: "add recurrence and recur_from",
+		Apply: func(doc map[string]any) error {
+			return nil
+		},
+	})
+
+	// Due dates were stored at midnight only when no time was given, so
+	// those become all-day due dates. Their zone is unknown and left empty.
+	registerMigration(Migration{
+		From:        7,
+		Description: "add due_all_day and due_zone",
+		Apply: func(doc map[string]any) error {
+			tasks, _ := doc["tasks"].([]any)
+			for _, t := range tasks {
+				task, ok := t.(map[string]any)
+				if !ok {
+					continue
+				}
+				value, _ := task["due_date"].(string)
+				if value == "" {
+					continue
+				}
+				due, err := time.Parse(time.RFC3339Nano, value)
+				if err != nil {
+					return fmt.Errorf("invalid due_date %q: %w", value, err)
+				}
+				if due.Hour() == 0 && due.Minute() == 0 && due.Second() == 0 {
+					task["due_all_day"] = true
+				}
+			}
+			return nil
+		},
+	})
+
+	registerMigration(Migration{
+		From:        8,
+		Description: "add next_occurrence",
+		Apply: func(doc map[string]any) error {
+			return nil
+		},
+	})
+}
+
+// schemaVersion returns the schema_version of an encoded tasks document
+func schemaVersion(data []byte) (int, error) {
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return header.SchemaVersion, nil
+}
+
+// migrateTaskList upgrades an encoded tasks document to CurrentSchemaVersion,
+// applying registered migrations one version at a time. It returns the
+// upgraded document and the version it started from.
+func migrateTaskList(data []byte) ([]byte, int, error) {
+	version, err := schemaVersion(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if version > CurrentSchemaVersion {
+		return nil, version, fmt.Errorf("%w (file schema version %d, supported up to %d)", ErrSchemaTooNew, version, CurrentSchemaVersion)
+	}
+	if version == CurrentSchemaVersion {
+		return data, version, nil
+	}
+
+	// Decode numbers as json.Number so IDs and revisions survive untouched
+	var doc map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, version, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	for v := version; v < CurrentSchemaVersion; v++ {
+		migration, ok := migrations[v]
+		if !ok {
+			return nil, version, fmt.Errorf("no migration from schema version %d", v)
+		}
+		if err := migration.Apply(doc); err != nil {
+			return nil, version, fmt.Errorf("migration from schema version %d (%s) failed: %w", v, migration.Description, err)
+		}
+		doc["schema_version"] = v + 1
+	}
+
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return nil, version, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return migrated, version, nil
+}
+
+// preMigrationPath returns where the original file is kept before it is
+// upgraded from the given schema version
+func preMigrationPath(filePath string, version int) string {
+	return fmt.Sprintf("%s.schema-v%d.bak", filePath, version)
+}
+
+// keepPreMigrationCopy saves the original data before a migration, unless a
+// copy for that version already exists
+func keepPreMigrationCopy(filePath string, version int, data []byte) error {
+	path := preMigrationPath(filePath, version)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to keep pre-migration copy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMigrateToCurrentSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		doc     string
+	}{
+		{"v0 without schema_version", 0, `{
+  "last_update": "alice",
+  "next_id": 4,
+  "tasks": [
+    {"id": 1, "title": "File taxes", "completed": true, "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-02T10:00:00Z"},
+    {"id": 2, "title": "Renew passport", "completed": false, "priority": "low", "due_date": "2025-02-01T00:00:00+01:00", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-01T09:00:00Z"},
+    {"id": 3, "title": "Call the bank", "completed": false, "priority": "high", "due_date": "2025-02-01T17:30:00Z", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-01T09:00:00Z"}
+  ]
+}`},
+		{"v1", 1, `{
+  "schema_version": 1,
+  "next_id": 4,
+  "revision": 7,
+  "modified_by": "alice",
+  "tasks": [
+    {"id": 1, "title": "File taxes", "completed": true, "priority": "medium", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-02T10:00:00Z"},
+    {"id": 2, "title": "Renew passport", "completed": false, "priority": "low", "due_date": "2025-02-01T00:00:00+01:00", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-01T09:00:00Z"},
+    {"id": 3, "title": "Call the bank", "completed": false, "priority": "high", "due_date": "2025-02-01T17:30:00Z", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-01T09:00:00Z"}
+  ]
+}`},
+		{"v7", 7, `{
+  "schema_version": 7,
+  "next_id": 4,
+  "revision": 7,
+  "tasks": [
+    {"id": 1, "title": "File taxes", "completed": true, "priority": "medium", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-02T10:00:00Z", "completed_at": "2025-01-02T10:00:00Z"},
+    {"id": 2, "title": "Renew passport", "completed": false, "priority": "low", "due_date": "2025-02-01T00:00:00+01:00", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-01T09:00:00Z"},
+    {"id": 3, "title": "Call the bank", "completed": false, "priority": "high", "due_date": "2025-02-01T17:30:00Z", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-01T09:00:00Z"}
+  ]
+}`},
+		{"v9 is left alone", CurrentSchemaVersion, `{
+  "schema_version": 9,
+  "next_id": 4,
+  "revision": 7,
+  "tasks": [
+    {"id": 1, "title": "File taxes", "completed": true, "priority": "medium", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-02T10:00:00Z", "completed_at": "2025-01-02T10:00:00Z"},
+    {"id": 2, "title": "Renew passport", "completed": false, "priority": "low", "due_date": "2025-02-01T00:00:00+01:00", "due_all_day": true, "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-01T09:00:00Z"},
+    {"id": 3, "title": "Call the bank", "completed": false, "priority": "high", "due_date": "2025-02-01T17:30:00Z", "created_at": "2025-01-01T09:00:00Z", "updated_at": "2025-01-01T09:00:00Z"}
+  ]
+}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tasks.json")
+			original := []byte(tt.doc)
+			if err := os.WriteFile(path, original, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			fs := NewFileStorage(path)
+			tasks, nextID, err := fs.LoadTasks()
+			if err != nil {
+				t.Fatalf("LoadTasks() error = %v", err)
+			}
+			checkMigratedTasks(t, tasks, nextID)
+
+			// The original of an older file is kept; a current file needs no copy
+			copyPath := preMigrationPath(path, tt.version)
+			kept, err := os.ReadFile(copyPath)
+			switch {
+			case tt.version == CurrentSchemaVersion:
+				if err == nil {
+					t.Errorf("pre-migration copy %s written for a current file", copyPath)
+				}
+			case err != nil:
+				t.Fatalf("pre-migration copy: %v", err)
+			case !bytes.Equal(kept, original):
+				t.Errorf("pre-migration copy differs from the original file")
+			}
+
+			// Saving writes the current layout; the copy stays as it was
+			if err := fs.SaveTasks(tasks, nextID); err != nil {
+				t.Fatalf("SaveTasks() error = %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version, err := schemaVersion(data); err != nil || version != CurrentSchemaVersion {
+				t.Errorf("saved schema version = %d, %v, want %d", version, err, CurrentSchemaVersion)
+			}
+			if tt.version != CurrentSchemaVersion {
+				if kept, err := os.ReadFile(copyPath); err != nil || !bytes.Equal(kept, original) {
+					t.Errorf("pre-migration copy changed by saving: %v", err)
+				}
+			}
+
+			tasks, nextID, err = NewFileStorage(path).LoadTasks()
+			if err != nil {
+				t.Fatalf("LoadTasks() after save error = %v", err)
+			}
+			checkMigratedTasks(t, tasks, nextID)
+		})
+	}
+}
+
+// checkMigratedTasks checks the tasks of the migration test documents as
+// they read in the current schema
+func checkMigratedTasks(t *testing.T, tasks []*Task, nextID int) {
+	t.Helper()
+
+	if len(tasks) != 3 || nextID != 4 {
+		t.Fatalf("got %d tasks and next ID %d, want 3 and 4", len(tasks), nextID)
+	}
+
+	done := tasks[0]
+	if done.Priority != PriorityMedium {
+		t.Errorf("task 1 priority = %q, want %q", done.Priority, PriorityMedium)
+	}
+	if want := time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC); done.CompletedAt == nil || !done.CompletedAt.Equal(want) {
+		t.Errorf("task 1 completed at %v, want %v", done.CompletedAt, want)
+	}
+
+	if !tasks[1].DueAllDay {
+		t.Errorf("task 2 due at midnight is not all-day")
+	}
+	if tasks[2].DueAllDay {
+		t.Errorf("task 3 due at 17:30 is all-day")
+	}
+	if tasks[1].DueZone != "" || tasks[2].DueZone != "" {
+		t.Errorf("due zones = %q, %q, want none", tasks[1].DueZone, tasks[2].DueZone)
+	}
+}
+
+func TestMigrateRejectsNewerSchema(t *testing.T) {
+	doc, err := json.Marshal(map[string]any{"schema_version": CurrentSchemaVersion + 1, "tasks": []any{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(path, doc, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := NewFileStorage(path).LoadTasks(); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("LoadTasks() error = %v, want ErrSchemaTooNew", err)
+	}
+}
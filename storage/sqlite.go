@@ -0,0 +1,830 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// Pure-Go SQLite driver, registered as "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+// DefaultSQLiteFileName is the name of the SQLite tasks database
+const DefaultSQLiteFileName = "tasks.db"
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS tasks (
+	id           INTEGER PRIMARY KEY,
+	title        TEXT    NOT NULL,
+	completed    INTEGER NOT NULL DEFAULT 0,
+	due_date     INTEGER,
+	due_all_day  INTEGER NOT NULL DEFAULT 0,
+	due_zone     TEXT    NOT NULL DEFAULT '',
+	priority     TEXT    NOT NULL,
+	created_at   INTEGER NOT NULL,
+	updated_at   INTEGER NOT NULL,
+	completed_at INTEGER,
+	project      TEXT    NOT NULL DEFAULT '',
+	parent_id    INTEGER NOT NULL DEFAULT 0,
+	recurrence   TEXT    NOT NULL DEFAULT '',
+	recur_from   TEXT    NOT NULL DEFAULT '',
+	next_occurrence INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS task_tags (
+	task_id INTEGER NOT NULL,
+	tag     TEXT    NOT NULL,
+	PRIMARY KEY (task_id, tag)
+);
+CREATE TABLE IF NOT EXISTS task_dependencies (
+	task_id    INTEGER NOT NULL,
+	depends_on INTEGER NOT NULL,
+	PRIMARY KEY (task_id, depends_on)
+);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+INSERT OR IGNORE INTO meta (key, value) VALUES ('revision', '0');
+CREATE INDEX IF NOT EXISTS idx_tasks_priority ON tasks(priority);
+CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks(due_date);
+CREATE INDEX IF NOT EXISTS idx_tasks_completed ON tasks(completed);
+CREATE INDEX IF NOT EXISTS idx_task_tags_tag ON task_tags(tag);
+CREATE INDEX IF NOT EXISTS idx_tasks_project ON tasks(project);
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
+`
+
+// sqliteSchemaVersion is the database layout this binary reads and writes.
+// It is stored in PRAGMA user_version; databases created before versioning
+// report 0 and are treated as version 1.
+const sqliteSchemaVersion = 9
+
+// sqliteMigrations upgrade an existing database from the version they are
+// keyed by to the next one. sqliteSchema always creates the newest layout.
+var sqliteMigrations = map[int]string{
+	1: `ALTER TABLE tasks ADD COLUMN completed_at INTEGER;
+UPDATE tasks SET completed_at = updated_at WHERE completed = 1;`,
+	2: `CREATE TABLE IF NOT EXISTS task_tags (
+	task_id INTEGER NOT NULL,
+	tag     TEXT    NOT NULL,
+	PRIMARY KEY (task_id, tag)
+);`,
+	3: `ALTER TABLE tasks ADD COLUMN project TEXT NOT NULL DEFAULT '';`,
+	4: `ALTER TABLE tasks ADD COLUMN parent_id INTEGER NOT NULL DEFAULT 0;`,
+	5: `CREATE TABLE IF NOT EXISTS task_dependencies (
+	task_id    INTEGER NOT NULL,
+	depends_on INTEGER NOT NULL,
+	PRIMARY KEY (task_id, depends_on)
+);`,
+	6: `ALTER TABLE tasks ADD COLUMN recurrence TEXT NOT NULL DEFAULT '';
+ALTER TABLE tasks ADD COLUMN recur_from TEXT NOT NULL DEFAULT '';`,
+	// Due dates were stored without a time only when none was given
+	7: `ALTER TABLE tasks ADD COLUMN due_all_day INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN due_zone TEXT NOT NULL DEFAULT '';
+UPDATE tasks SET due_all_day = 1
+WHERE due_date IS NOT NULL AND strftime('%H:%M:%S', due_date / 1000000000, 'unixepoch', 'localtime') = '00:00:00';`,
+	8: `ALTER TABLE tasks ADD COLUMN next_occurrence INTEGER NOT NULL DEFAULT 0;`,
+}
+
+const sqliteTaskColumns = "id, title, completed, due_date, due_all_day, due_zone, priority, created_at, updated_at, completed_at, project, parent_id, recurrence, recur_from, next_occurrence"
+
+// SQLiteStorage handles saving and loading tasks to/from an embedded SQLite database
+type SQLiteStorage struct {
+	filePath        string
+	lockTimeout     time.Duration
+	backupRetention int
+	revision        int64         // revision of the database as of the last load or save
+	saved           map[int]*Task // tasks as of the last load or save; nil if unknown
+	db              *sql.DB
+}
+
+// NewSQLiteStorage creates a new SQLite storage instance. The database is
+// opened lazily on first use.
+func NewSQLiteStorage(customPath ...string) *SQLiteStorage {
+	filePath := defaultPath(DefaultSQLiteFileName)
+	if len(customPath) > 0 && customPath[0] != "" {
+		filePath = customPath[0]
+	}
+
+	return &SQLiteStorage{
+		filePath:        filePath,
+		lockTimeout:     DefaultLockTimeout,
+		backupRetention: DefaultBackupRetention,
+	}
+}
+
+// SetLockTimeout sets how long a write waits for another process to release
+// the database
+func (ss *SQLiteStorage) SetLockTimeout(timeout time.Duration) {
+	ss.lockTimeout = timeout
+}
+
+// open opens the database and creates the schema if needed
+func (ss *SQLiteStorage) open() (*sql.DB, error) {
+	if ss.db != nil {
+		return ss.db, nil
+	}
+
+	if err := ensureParentDir(ss.filePath); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", ss.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite locks the database itself; keep a single connection so the
+	// busy timeout applies to every statement
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA busy_timeout = %d", ss.lockTimeout.Milliseconds())); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to configure database: %w", err)
+	}
+
+	if err := ss.migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	ss.db = db
+	return db, nil
+}
+
+// migrate upgrades an existing database to sqliteSchemaVersion, keeping a
+// copy of the original first. New databases are stamped with the current version.
+func (ss *SQLiteStorage) migrate(db *sql.DB) error {
+	var tables int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'tasks'").Scan(&tables); err != nil {
+		return fmt.Errorf("failed to inspect database: %w", err)
+	}
+
+	if tables == 0 {
+		if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", sqliteSchemaVersion)); err != nil {
+			return fmt.Errorf("failed to set schema version: %w", err)
+		}
+		return nil
+	}
+
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version == 0 {
+		version = 1
+	}
+
+	if version > sqliteSchemaVersion {
+		return fmt.Errorf("%w (database schema version %d, supported up to %d)", ErrSchemaTooNew, version, sqliteSchemaVersion)
+	}
+	if version == sqliteSchemaVersion {
+		return nil
+	}
+
+	// Keep the original database before changing it
+	backupPath := preMigrationPath(ss.filePath, version)
+	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+		if _, err := db.Exec("VACUUM INTO ?", backupPath); err != nil {
+			return fmt.Errorf("failed to keep pre-migration copy: %w", err)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for v := version; v < sqliteSchemaVersion; v++ {
+		migration, ok := sqliteMigrations[v]
+		if !ok {
+			return fmt.Errorf("no migration from schema version %d", v)
+		}
+		if _, err := tx.Exec(migration); err != nil {
+			return fmt.Errorf("migration from schema version %d failed: %w", v, err)
+		}
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", sqliteSchemaVersion)); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// Open opens the database, upgrading an older schema. Every other method
+// opens it on first use; Open lets callers report a damaged or too new
+// database before reading any tasks.
+func (ss *SQLiteStorage) Open() error {
+	_, err := ss.open()
+	return err
+}
+
+// Close closes the underlying database
+func (ss *SQLiteStorage) Close() error {
+	if ss.db == nil {
+		return nil
+	}
+	err := ss.db.Close()
+	ss.db = nil
+	return err
+}
+
+// LoadTasks loads all tasks from the database. Everything is read in one
+// transaction, so the tasks, the next ID and the revision always belong
+// together even while other processes save.
+func (ss *SQLiteStorage) LoadTasks() ([]*Task, int, error) {
+	db, err := ss.open()
+	if err != nil {
+		return nil, 1, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, 1, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var revision int64
+	if err := tx.QueryRow("SELECT CAST(value AS INTEGER) FROM meta WHERE key = 'revision'").Scan(&revision); err != nil {
+		return nil, 1, fmt.Errorf("failed to read revision: %w", err)
+	}
+
+	tasks, err := ss.queryTasks(tx, "")
+	if err != nil {
+		return nil, 1, err
+	}
+
+	nextID, err := ss.loadNextID(tx)
+	if err != nil {
+		return nil, 1, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, 1, fmt.Errorf("failed to finish reading tasks: %w", err)
+	}
+	ss.revision = revision
+	ss.saved = snapshotTasks(tasks)
+
+	// Never hand out an ID that is already taken
+	for _, task := range tasks {
+		if task.ID >= nextID {
+			nextID = task.ID + 1
+		}
+	}
+
+	return tasks, nextID, nil
+}
+
+// SaveTasks synchronises the database with tasks, storing exactly what
+// FileStorage would. Only tasks that differ from the last load or save are
+// written, and only their changed tags and dependencies; rows of removed
+// tasks are deleted. Like FileStorage it returns an error matching
+// ErrConflict if another process saved since the tasks were loaded.
+func (ss *SQLiteStorage) SaveTasks(tasks []*Task, nextID int) error {
+	db, err := ss.open()
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Bump the revision first so this transaction holds the write lock
+	// before anything is read
+	var revision int64
+	if err := tx.QueryRow(
+		"UPDATE meta SET value = CAST(value AS INTEGER) + 1 WHERE key = 'revision' RETURNING CAST(value AS INTEGER)",
+	).Scan(&revision); err != nil {
+		return fmt.Errorf("failed to update revision: %w", err)
+	}
+	if revision-1 != ss.revision {
+		return &ConflictError{Expected: ss.revision, Found: revision - 1}
+	}
+
+	// The revision matched, so the database still holds the saved tasks.
+	// Without them, every stored row is compared.
+	saved := ss.saved
+	if saved == nil {
+		stored, err := ss.queryTasks(tx, "")
+		if err != nil {
+			return err
+		}
+		saved = snapshotTasks(stored)
+	}
+
+	deleteTags, err := tx.Prepare("DELETE FROM task_tags WHERE task_id = ?")
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer deleteTags.Close()
+
+	insertTag, err := tx.Prepare("INSERT OR IGNORE INTO task_tags (task_id, tag) VALUES (?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer insertTag.Close()
+
+	deleteDeps, err := tx.Prepare("DELETE FROM task_dependencies WHERE task_id = ?")
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer deleteDeps.Close()
+
+	insertDep, err := tx.Prepare("INSERT OR IGNORE INTO task_dependencies (task_id, depends_on) VALUES (?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer insertDep.Close()
+
+	upsert, err := tx.Prepare(`INSERT INTO tasks (` + sqliteTaskColumns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+	title = excluded.title,
+	completed = excluded.completed,
+	due_date = excluded.due_date,
+	due_all_day = excluded.due_all_day,
+	due_zone = excluded.due_zone,
+	priority = excluded.priority,
+	created_at = excluded.created_at,
+	updated_at = excluded.updated_at,
+	completed_at = excluded.completed_at,
+	project = excluded.project,
+	parent_id = excluded.parent_id,
+	recurrence = excluded.recurrence,
+	recur_from = excluded.recur_from,
+	next_occurrence = excluded.next_occurrence`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer upsert.Close()
+
+	kept := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		kept[task.ID] = true
+		old, existed := saved[task.ID]
+
+		if !existed || !reflect.DeepEqual(taskRow(old), taskRow(task)) {
+			if _, err := upsert.Exec(taskRow(task)...); err != nil {
+				return fmt.Errorf("failed to save task %d: %w", task.ID, err)
+			}
+		}
+
+		if !existed || !slices.Equal(old.Tags, task.Tags) {
+			if _, err := deleteTags.Exec(task.ID); err != nil {
+				return fmt.Errorf("failed to save tags of task %d: %w", task.ID, err)
+			}
+			for _, tag := range task.Tags {
+				if _, err := insertTag.Exec(task.ID, tag); err != nil {
+					return fmt.Errorf("failed to save tags of task %d: %w", task.ID, err)
+				}
+			}
+		}
+
+		if !existed || !slices.Equal(old.DependsOn, task.DependsOn) {
+			if _, err := deleteDeps.Exec(task.ID); err != nil {
+				return fmt.Errorf("failed to save dependencies of task %d: %w", task.ID, err)
+			}
+			for _, dependsOn := range task.DependsOn {
+				if _, err := insertDep.Exec(task.ID, dependsOn); err != nil {
+					return fmt.Errorf("failed to save dependencies of task %d: %w", task.ID, err)
+				}
+			}
+		}
+	}
+
+	for id := range saved {
+		if kept[id] {
+			continue
+		}
+		if _, err := tx.Exec("DELETE FROM tasks WHERE id = ?", id); err != nil {
+			return fmt.Errorf("failed to delete task %d: %w", id, err)
+		}
+		if _, err := deleteTags.Exec(id); err != nil {
+			return fmt.Errorf("failed to delete tags of task %d: %w", id, err)
+		}
+		if _, err := deleteDeps.Exec(id); err != nil {
+			return fmt.Errorf("failed to delete dependencies of task %d: %w", id, err)
+		}
+	}
+
+	if _, err := tx.Exec(
+		"INSERT INTO meta (key, value) VALUES ('next_id', ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value",
+		strconv.Itoa(nextID),
+	); err != nil {
+		return fmt.Errorf("failed to save next ID: %w", err)
+	}
+
+	if _, err := tx.Exec(
+		"INSERT INTO meta (key, value) VALUES ('last_modified', ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value",
+		time.Now().Format(time.RFC3339Nano),
+	); err != nil {
+		return fmt.Errorf("failed to save modification time: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	ss.revision = revision
+	ss.saved = snapshotTasks(tasks)
+	return nil
+}
+
+// taskRow returns the column values of task in sqliteTaskColumns order
+func taskRow(task *Task) []any {
+	var dueDate, completedAt sql.NullInt64
+	if task.DueDate != nil {
+		dueDate = sql.NullInt64{Int64: task.DueDate.UnixNano(), Valid: true}
+	}
+	if task.CompletedAt != nil {
+		completedAt = sql.NullInt64{Int64: task.CompletedAt.UnixNano(), Valid: true}
+	}
+
+	return []any{
+		task.ID,
+		task.Title,
+		task.Completed,
+		dueDate,
+		task.DueAllDay,
+		task.DueZone,
+		string(task.Priority),
+		task.CreatedAt.UnixNano(),
+		task.UpdatedAt.UnixNano(),
+		completedAt,
+		task.Project,
+		task.ParentID,
+		task.Recurrence,
+		task.RecurFrom,
+		task.NextOccurrence,
+	}
+}
+
+// snapshotTasks copies tasks by ID, so later changes to them are noticed
+func snapshotTasks(tasks []*Task) map[int]*Task {
+	snapshot := make(map[int]*Task, len(tasks))
+	for _, task := range cloneTasks(tasks) {
+		snapshot[task.ID] = task
+	}
+	return snapshot
+}
+
+// QueryTasks returns the tasks matching q, evaluated by SQLite using the
+// priority, due date, completion and project indexes
+func (ss *SQLiteStorage) QueryTasks(q Query) ([]*Task, error) {
+	db, err := ss.open()
+	if err != nil {
+		return nil, err
+	}
+
+	var where []string
+	var args []any
+
+	if q.Completed != nil {
+		where = append(where, "completed = ?")
+		args = append(args, *q.Completed)
+	}
+
+	if q.Priority != "" {
+		where = append(where, "priority = ?")
+		args = append(args, string(q.Priority))
+	}
+
+	if q.Project != "" {
+		where = append(where, `(project = ? OR project LIKE ? ESCAPE '\')`)
+		args = append(args, q.Project, escapeLike(q.Project)+".%")
+	}
+
+	for _, tag := range q.Tags {
+		where = append(where, "EXISTS (SELECT 1 FROM task_tags WHERE task_id = tasks.id AND tag = ?)")
+		args = append(args, tag)
+	}
+
+	for _, tag := range q.ExcludeTags {
+		where = append(where, "NOT EXISTS (SELECT 1 FROM task_tags WHERE task_id = tasks.id AND tag = ?)")
+		args = append(args, tag)
+	}
+
+	if q.NoDue {
+		where = append(where, "due_date IS NULL")
+	}
+
+	// Times are stored as nanoseconds, so the ranges compare directly
+	ranges := []struct {
+		column string
+		r      TimeRange
+	}{
+		{"due_date", q.Due},
+		{"created_at", q.Created},
+		{"completed_at", q.CompletedAt},
+	}
+	for _, rc := range ranges {
+		if rc.r.From != nil {
+			where = append(where, rc.column+" >= ?")
+			args = append(args, rc.r.From.UnixNano())
+		}
+		if rc.r.To != nil {
+			where = append(where, rc.column+" < ?")
+			args = append(args, rc.r.To.UnixNano())
+		}
+	}
+
+	return ss.queryTasks(db, strings.Join(where, " AND "), args...)
+}
+
+// GetFilePath returns the database file path
+func (ss *SQLiteStorage) GetFilePath() string {
+	return ss.filePath
+}
+
+// Describe returns the database file path
+func (ss *SQLiteStorage) Describe() string {
+	return ss.filePath
+}
+
+// SetBackupRetention sets how many backups BackupTasks keeps. Zero or less
+// keeps every backup.
+func (ss *SQLiteStorage) SetBackupRetention(retention int) {
+	ss.backupRetention = retention
+}
+
+// BackupTasks writes a consistent, timestamped copy of the database and
+// removes the oldest backups beyond the retention count
+func (ss *SQLiteStorage) BackupTasks() (*Backup, error) {
+	return ss.backupTasks("")
+}
+
+// backupTasks creates a backup, never pruning the backup at keep
+func (ss *SQLiteStorage) backupTasks(keep string) (*Backup, error) {
+	db, err := ss.open()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	path := backupPath(ss.filePath, now)
+	if err := ensureParentDir(path); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if _, err := db.Exec("VACUUM INTO ?", path); err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	if err := pruneBackupFiles(ss.filePath, ss.backupRetention, keep); err != nil {
+		return nil, err
+	}
+
+	return listedBackupFile(ss.filePath, path)
+}
+
+// ListBackups returns the available backups, newest first
+func (ss *SQLiteStorage) ListBackups() ([]Backup, error) {
+	return listBackupFiles(ss.filePath)
+}
+
+// RestoreBackup replaces the database with the backup identified by ref
+// (an ID from ListBackups or a timestamp). The backup is checked for
+// integrity before anything is changed, and the current database is backed
+// up first.
+func (ss *SQLiteStorage) RestoreBackup(ref string) (*Backup, error) {
+	backups, err := ss.ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := findBackup(backups, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSQLiteBackup(backup.Path); err != nil {
+		return nil, fmt.Errorf("backup %s is not usable: %w", backup.Path, err)
+	}
+
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	// Keep what is being replaced and remember its revision
+	if _, err := ss.backupTasks(backup.Path); err != nil {
+		return nil, fmt.Errorf("failed to back up current tasks: %w", err)
+	}
+
+	db, err := ss.open()
+	if err != nil {
+		return nil, err
+	}
+	var revision int64
+	if err := db.QueryRow("SELECT CAST(value AS INTEGER) FROM meta WHERE key = 'revision'").Scan(&revision); err != nil {
+		return nil, fmt.Errorf("failed to read revision: %w", err)
+	}
+
+	if err := ss.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close database: %w", err)
+	}
+
+	if err := writeFileAtomic(ss.filePath, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to restore backup (existing database left unchanged): %w", err)
+	}
+
+	// Reopen (upgrading older backups) and move past the replaced revision
+	// so other processes notice the change
+	db, err = ss.open()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(
+		"INSERT INTO meta (key, value) VALUES ('revision', ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value",
+		strconv.FormatInt(revision+1, 10),
+	); err != nil {
+		return nil, fmt.Errorf("failed to update revision: %w", err)
+	}
+	ss.revision = revision + 1
+	ss.saved = nil
+
+	return backup, nil
+}
+
+// validateSQLiteBackup checks that a backup is an intact task database this
+// binary can read
+func validateSQLiteBackup(path string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > sqliteSchemaVersion {
+		return fmt.Errorf("%w (database schema version %d, supported up to %d)", ErrSchemaTooNew, version, sqliteSchemaVersion)
+	}
+
+	var result string
+	if err := db.QueryRow("PRAGMA integrity_check").Scan(&result); err != nil {
+		return err
+	}
+	if result != "ok" {
+		return fmt.Errorf("integrity check failed: %s", result)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count); err != nil {
+		return fmt.Errorf("no task table: %w", err)
+	}
+	return nil
+}
+
+// sqlQueryer runs queries on a database or within a transaction
+type sqlQueryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// queryTasks returns the tasks matching the SQL condition where (all tasks if
+// empty), ordered by ID and with their tags and dependencies
+func (ss *SQLiteStorage) queryTasks(db sqlQueryer, where string, args ...any) ([]*Task, error) {
+	query := "SELECT " + sqliteTaskColumns + " FROM tasks"
+	tagQuery := "SELECT task_id, tag FROM task_tags"
+	depQuery := "SELECT task_id, depends_on FROM task_dependencies"
+	if where != "" {
+		query += " WHERE " + where
+		tagQuery += " WHERE task_id IN (SELECT id FROM tasks WHERE " + where + ")"
+		depQuery += " WHERE task_id IN (SELECT id FROM tasks WHERE " + where + ")"
+	}
+	query += " ORDER BY id"
+	tagQuery += " ORDER BY rowid"
+	depQuery += " ORDER BY rowid"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []*Task{}
+	for rows.Next() {
+		var (
+			task        Task
+			priority    string
+			dueDate     sql.NullInt64
+			createdAt   int64
+			updatedAt   int64
+			completedAt sql.NullInt64
+		)
+		if err := rows.Scan(&task.ID, &task.Title, &task.Completed, &dueDate, &task.DueAllDay, &task.DueZone, &priority, &createdAt, &updatedAt, &completedAt, &task.Project, &task.ParentID, &task.Recurrence, &task.RecurFrom, &task.NextOccurrence); err != nil {
+			return nil, fmt.Errorf("failed to read task: %w", err)
+		}
+
+		task.Priority = Priority(priority)
+		task.CreatedAt = time.Unix(0, createdAt)
+		task.UpdatedAt = time.Unix(0, updatedAt)
+		if dueDate.Valid {
+			due := time.Unix(0, dueDate.Int64)
+			task.DueDate = &due
+		}
+		if completedAt.Valid {
+			completed := time.Unix(0, completedAt.Int64)
+			task.CompletedAt = &completed
+		}
+
+		tasks = append(tasks, &task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	err = attachRows(db, tasks, tagQuery, args, func(task *Task, tag string) {
+		task.Tags = append(task.Tags, tag)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = attachRows(db, tasks, depQuery, args, func(task *Task, dependsOn int) {
+		task.DependsOn = append(task.DependsOn, dependsOn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// attachRows runs query, which returns (task_id, value) rows, and passes each
+// value to add together with its task
+func attachRows[T any](db sqlQueryer, tasks []*Task, query string, args []any, add func(task *Task, value T)) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query related rows: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID int
+			value  T
+		)
+		if err := rows.Scan(&taskID, &value); err != nil {
+			return fmt.Errorf("failed to read related row: %w", err)
+		}
+		if task, ok := byID[taskID]; ok {
+			add(task, value)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read related rows: %w", err)
+	}
+	return nil
+}
+
+// loadNextID reads the stored next task ID, defaulting to 1
+func (ss *SQLiteStorage) loadNextID(db sqlQueryer) (int, error) {
+	var value string
+	err := db.QueryRow("SELECT value FROM meta WHERE key = 'next_id'").Scan(&value)
+	if err == sql.ErrNoRows {
+		return 1, nil
+	}
+	if err != nil {
+		return 1, fmt.Errorf("failed to read next ID: %w", err)
+	}
+
+	nextID, err := strconv.Atoi(value)
+	if err != nil || nextID < 1 {
+		return 1, nil
+	}
+	return nextID, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "%", `\%`)
+	return strings.ReplaceAll(s, "_", `\_`)
+}
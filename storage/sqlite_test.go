@@ -0,0 +1,357 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSQLiteConcurrentWritersKeepEveryTask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	// Create the database up front so the writers only race on saving
+	if err := NewSQLiteStorage(path).SaveTasks(nil, 1); err != nil {
+		t.Fatalf("SaveTasks() error = %v", err)
+	}
+
+	const writers, tasksEach = 20, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Each writer stands for a separate process with its own connection
+			ss := NewSQLiteStorage(path)
+			ss.SetLockTimeout(10 * time.Second)
+			defer ss.Close()
+
+			for n := 0; n < tasksEach; n++ {
+				if err := addTask(ss, fmt.Sprintf("task %d from writer %d", n, i)); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("writer failed: %v", err)
+	}
+
+	tasks, nextID, err := NewSQLiteStorage(path).LoadTasks()
+	if err != nil {
+		t.Fatalf("LoadTasks() error = %v", err)
+	}
+	if len(tasks) != writers*tasksEach {
+		t.Fatalf("got %d tasks, want %d", len(tasks), writers*tasksEach)
+	}
+	for i, task := range tasks {
+		if task.ID != i+1 {
+			t.Fatalf("task %d has ID %d, want %d", i, task.ID, i+1)
+		}
+	}
+	if nextID != writers*tasksEach+1 {
+		t.Errorf("next ID = %d, want %d", nextID, writers*tasksEach+1)
+	}
+}
+
+// addTask adds a task the way the task manager does: load, append, save,
+// and start over if another writer saved in between
+func addTask(ss *SQLiteStorage, title string) error {
+	for attempt := 0; attempt < 1000; attempt++ {
+		tasks, nextID, err := ss.LoadTasks()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		tasks = append(tasks, &Task{
+			ID:        nextID,
+			Title:     title,
+			Priority:  PriorityMedium,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+
+		err = ss.SaveTasks(tasks, nextID+1)
+		if !errors.Is(err, ErrConflict) {
+			return err
+		}
+	}
+	return fmt.Errorf("%s: kept conflicting with other writers", title)
+}
+
+func TestSQLiteSavesChangesWithoutNewUpdateTime(t *testing.T) {
+	ss := NewSQLiteStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	defer ss.Close()
+
+	now := time.Now()
+	tasks := []*Task{
+		{ID: 1, Title: "first", Priority: PriorityLow, CreatedAt: now, UpdatedAt: now, Tags: []string{"home"}},
+		{ID: 2, Title: "second", Priority: PriorityLow, CreatedAt: now, UpdatedAt: now, DependsOn: []int{1}},
+	}
+	if err := ss.SaveTasks(tasks, 3); err != nil {
+		t.Fatalf("SaveTasks() error = %v", err)
+	}
+
+	// Tag renames and dependency rewrites keep the update time
+	tasks[0].Tags = []string{"house"}
+	tasks[0].Title = "first, renamed"
+	tasks[1].DependsOn = nil
+	if err := ss.SaveTasks(tasks, 3); err != nil {
+		t.Fatalf("SaveTasks() error = %v", err)
+	}
+
+	loaded, _, err := NewSQLiteStorage(ss.GetFilePath()).LoadTasks()
+	if err != nil {
+		t.Fatalf("LoadTasks() error = %v", err)
+	}
+	if got := loaded[0].Title; got != "first, renamed" {
+		t.Errorf("title = %q, want %q", got, "first, renamed")
+	}
+	if got := loaded[0].Tags; len(got) != 1 || got[0] != "house" {
+		t.Errorf("tags = %v, want [house]", got)
+	}
+	if got := loaded[1].DependsOn; len(got) != 0 {
+		t.Errorf("depends on = %v, want none", got)
+	}
+}
+
+func TestJSONSQLiteRoundTrip(t *testing.T) {
+	due := time.Date(2026, time.March, 29, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	allDay := time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2026, time.January, 5, 8, 15, 30, 123456789, time.UTC)
+	completed := created.Add(36 * time.Hour)
+
+	want := []*Task{
+		{ID: 1, Title: "Plan the release", Priority: PriorityHigh, CreatedAt: created, UpdatedAt: created,
+			Tags: []string{"work", "q2"}, Project: "work.backend"},
+		{ID: 2, Title: "Write notes, \"draft\" ünïcode\tand tabs", Priority: PriorityMedium, CreatedAt: created, UpdatedAt: completed,
+			Completed: true, CompletedAt: &completed, ParentID: 1},
+		{ID: 3, Title: "Ship it", Priority: PriorityLow, CreatedAt: created, UpdatedAt: created,
+			DueDate: &due, DueZone: "Europe/Berlin", DependsOn: []int{2, 1}, Project: "work"},
+		{ID: 5, Title: "Water plants", Priority: PriorityMedium, CreatedAt: created, UpdatedAt: created,
+			DueDate: &allDay, DueAllDay: true, Recurrence: "FREQ=DAILY;INTERVAL=3", RecurFrom: "completion",
+			Completed: true, CompletedAt: &completed, NextOccurrence: 6},
+	}
+	const nextID = 7
+
+	dir := t.TempDir()
+	ss := NewSQLiteStorage(filepath.Join(dir, "tasks.db"))
+	defer ss.Close()
+
+	// JSON to SQLite and back to a second JSON file
+	steps := []struct {
+		name    string
+		backend Backend
+	}{
+		{"json", NewFileStorage(filepath.Join(dir, "tasks.json"))},
+		{"sqlite", ss},
+		{"json again", NewFileStorage(filepath.Join(dir, "again.json"))},
+	}
+
+	tasks, next := want, nextID
+	for _, step := range steps {
+		if err := step.backend.SaveTasks(tasks, next); err != nil {
+			t.Fatalf("%s: SaveTasks() error = %v", step.name, err)
+		}
+
+		var err error
+		tasks, next, err = step.backend.LoadTasks()
+		if err != nil {
+			t.Fatalf("%s: LoadTasks() error = %v", step.name, err)
+		}
+		if next != nextID {
+			t.Errorf("%s: next ID = %d, want %d", step.name, next, nextID)
+		}
+		if !reflect.DeepEqual(normalizeTasks(tasks), normalizeTasks(want)) {
+			t.Errorf("%s: tasks changed in the round trip\ngot:  %s\nwant: %s", step.name, describeTasks(tasks), describeTasks(want))
+		}
+	}
+}
+
+// normalizeTasks copies tasks with times in UTC and empty lists as nil, so
+// tasks that store the same data compare equal
+func normalizeTasks(tasks []*Task) []Task {
+	utc := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		u := t.UTC()
+		return &u
+	}
+
+	normalized := make([]Task, len(tasks))
+	for i, task := range tasks {
+		n := *task
+		n.CreatedAt, n.UpdatedAt = task.CreatedAt.UTC(), task.UpdatedAt.UTC()
+		n.DueDate, n.CompletedAt = utc(task.DueDate), utc(task.CompletedAt)
+		if len(n.Tags) == 0 {
+			n.Tags = nil
+		}
+		if len(n.DependsOn) == 0 {
+			n.DependsOn = nil
+		}
+		normalized[i] = n
+	}
+	return normalized
+}
+
+// describeTasks formats tasks for failure messages
+func describeTasks(tasks []*Task) string {
+	var b strings.Builder
+	for _, task := range normalizeTasks(tasks) {
+		fmt.Fprintf(&b, "\n  %+v", task)
+	}
+	return b.String()
+}
+
+func TestSQLiteSavesOnlyChangedTasks(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(tasks []*Task) []*Task
+		want   []int // IDs of the tasks whose rows are written
+	}{
+		{"nothing changed", func(tasks []*Task) []*Task { return tasks }, nil},
+		{"title", func(tasks []*Task) []*Task {
+			tasks[1].Title = "renamed"
+			return tasks
+		}, []int{2}},
+		{"tags", func(tasks []*Task) []*Task {
+			tasks[0].Tags = append(tasks[0].Tags, "urgent")
+			return tasks
+		}, []int{1}},
+		{"dependencies", func(tasks []*Task) []*Task {
+			tasks[2].DependsOn = nil
+			return tasks
+		}, []int{3}},
+		{"added", func(tasks []*Task) []*Task {
+			return append(tasks, &Task{ID: 4, Title: "new", Priority: PriorityLow, CreatedAt: tasks[0].CreatedAt, UpdatedAt: tasks[0].CreatedAt, Tags: []string{"home"}})
+		}, []int{4}},
+		{"removed", func(tasks []*Task) []*Task { return tasks[:2] }, []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := NewSQLiteStorage(filepath.Join(t.TempDir(), "tasks.db"))
+			defer ss.Close()
+
+			now := time.Now()
+			err := ss.SaveTasks([]*Task{
+				{ID: 1, Title: "first", Priority: PriorityLow, CreatedAt: now, UpdatedAt: now, Tags: []string{"home"}},
+				{ID: 2, Title: "second", Priority: PriorityHigh, CreatedAt: now, UpdatedAt: now, DueDate: &now},
+				{ID: 3, Title: "third", Priority: PriorityLow, CreatedAt: now, UpdatedAt: now, DependsOn: []int{1, 2}},
+			}, 4)
+			if err != nil {
+				t.Fatalf("SaveTasks() error = %v", err)
+			}
+
+			tasks, nextID, err := ss.LoadTasks()
+			if err != nil {
+				t.Fatalf("LoadTasks() error = %v", err)
+			}
+
+			// Record every task row, tag and dependency the save touches
+			_, err = ss.db.Exec(`CREATE TEMP TABLE written (task_id INTEGER);
+CREATE TEMP TRIGGER tasks_insert AFTER INSERT ON tasks BEGIN INSERT INTO written VALUES (NEW.id); END;
+CREATE TEMP TRIGGER tasks_update AFTER UPDATE ON tasks BEGIN INSERT INTO written VALUES (NEW.id); END;
+CREATE TEMP TRIGGER tasks_delete AFTER DELETE ON tasks BEGIN INSERT INTO written VALUES (OLD.id); END;
+CREATE TEMP TRIGGER tags_insert AFTER INSERT ON task_tags BEGIN INSERT INTO written VALUES (NEW.task_id); END;
+CREATE TEMP TRIGGER tags_delete AFTER DELETE ON task_tags BEGIN INSERT INTO written VALUES (OLD.task_id); END;
+CREATE TEMP TRIGGER deps_insert AFTER INSERT ON task_dependencies BEGIN INSERT INTO written VALUES (NEW.task_id); END;
+CREATE TEMP TRIGGER deps_delete AFTER DELETE ON task_dependencies BEGIN INSERT INTO written VALUES (OLD.task_id); END;`)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			changed := tt.change(tasks)
+			if err := ss.SaveTasks(changed, nextID+1); err != nil {
+				t.Fatalf("SaveTasks() error = %v", err)
+			}
+
+			var written []int
+			rows, err := ss.db.Query("SELECT DISTINCT task_id FROM written ORDER BY task_id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var id int
+				if err := rows.Scan(&id); err != nil {
+					t.Fatal(err)
+				}
+				written = append(written, id)
+			}
+			if !reflect.DeepEqual(written, tt.want) {
+				t.Errorf("wrote tasks %v, want %v", written, tt.want)
+			}
+
+			// What was saved reads back in full
+			loaded, _, err := NewSQLiteStorage(ss.GetFilePath()).LoadTasks()
+			if err != nil {
+				t.Fatalf("LoadTasks() error = %v", err)
+			}
+			if !reflect.DeepEqual(normalizeTasks(loaded), normalizeTasks(changed)) {
+				t.Errorf("saved tasks differ\ngot:  %s\nwant: %s", describeTasks(loaded), describeTasks(changed))
+			}
+		})
+	}
+}
+
+func TestSQLiteQueryDateRanges(t *testing.T) {
+	day := func(d int) *time.Time {
+		t := time.Date(2026, time.March, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	ss := NewSQLiteStorage(filepath.Join(t.TempDir(), "tasks.db"))
+	defer ss.Close()
+	err := ss.SaveTasks([]*Task{
+		{ID: 1, Title: "due on the 1st", Priority: PriorityLow, CreatedAt: *day(1), UpdatedAt: *day(1), DueDate: day(1)},
+		{ID: 2, Title: "due on the 5th", Priority: PriorityLow, CreatedAt: *day(2), UpdatedAt: *day(2), DueDate: day(5)},
+		{ID: 3, Title: "no due date", Priority: PriorityLow, CreatedAt: *day(3), UpdatedAt: *day(4),
+			Completed: true, CompletedAt: day(4)},
+	}, 4)
+	if err != nil {
+		t.Fatalf("SaveTasks() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []int
+	}{
+		{"due from, inclusive", Query{Due: TimeRange{From: day(5)}}, []int{2}},
+		{"due to, exclusive", Query{Due: TimeRange{To: day(5)}}, []int{1}},
+		{"due within", Query{Due: TimeRange{From: day(1), To: day(6)}}, []int{1, 2}},
+		{"no due date", Query{NoDue: true}, []int{3}},
+		{"created", Query{Created: TimeRange{From: day(2), To: day(3)}}, []int{2}},
+		{"completed", Query{CompletedAt: TimeRange{From: day(1)}}, []int{3}},
+		{"completed before anything", Query{CompletedAt: TimeRange{To: day(1)}}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := ss.QueryTasks(tt.query)
+			if err != nil {
+				t.Fatalf("QueryTasks() error = %v", err)
+			}
+			var got []int
+			for _, task := range tasks {
+				got = append(got, task.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("QueryTasks(%+v) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ViewsFileName is the name of the file holding saved views, kept in the
+// same directory as the task list
+const ViewsFileName = "views.json"
+
+// View is a named set of list filters
+type View struct {
+	Name        string   `json:"name"`
+	Completed   bool     `json:"completed,omitempty"` // only completed tasks
+	Pending     bool     `json:"pending,omitempty"`   // only pending tasks
+	Priority    Priority `json:"priority,omitempty"`
+	Search      string   `json:"search,omitempty"`
+	SearchRegex bool     `json:"search_regex,omitempty"` // Search is a regular expression
+	Project     string   `json:"project,omitempty"`
+	Blocked     *bool    `json:"blocked,omitempty"` // only blocked (true) or unblocked (false) tasks
+	Tags        []string `json:"tags,omitempty"`
+	ExcludeTags []string `json:"exclude_tags,omitempty"`
+	Query       string   `json:"query,omitempty"`
+	Sort        string   `json:"sort,omitempty"`
+	GroupBy     string   `json:"group_by,omitempty"`
+
+	// Date filters are kept as written, so "yesterday" stays relative
+	DueBefore       string `json:"due_before,omitempty"`
+	DueAfter        string `json:"due_after,omitempty"`
+	DueWithin       string `json:"due_within,omitempty"`
+	Overdue         bool   `json:"overdue,omitempty"`
+	NoDue           bool   `json:"no_due,omitempty"`
+	CreatedBefore   string `json:"created_before,omitempty"`
+	CreatedAfter    string `json:"created_after,omitempty"`
+	CreatedSince    string `json:"created_since,omitempty"`
+	CompletedBefore string `json:"completed_before,omitempty"`
+	CompletedAfter  string `json:"completed_after,omitempty"`
+	CompletedSince  string `json:"completed_since,omitempty"`
+}
+
+// viewList is the structure stored in the views file
+type viewList struct {
+	Views []View `json:"views"`
+}
+
+// ViewsPath returns the views file belonging to the task list at taskFile
+func ViewsPath(taskFile string) string {
+	return filepath.Join(filepath.Dir(taskFile), ViewsFileName)
+}
+
+// LoadViews reads the views stored at path, sorted by name. A missing file
+// holds no views.
+func LoadViews(path string) ([]View, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read views file: %w", err)
+	}
+
+	var list viewList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse views file %s: %w", path, err)
+	}
+
+	sortViews(list.Views)
+	return list.Views, nil
+}
+
+// SaveViews replaces the views stored at path
+func SaveViews(path string, views []View) error {
+	if err := ensureParentDir(path); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	sorted := append([]View{}, views...)
+	sortViews(sorted)
+
+	data, err := json.MarshalIndent(viewList{Views: sorted}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal views: %w", err)
+	}
+
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write views file: %w", err)
+	}
+	return nil
+}
+
+// sortViews sorts views by name
+func sortViews(views []View) {
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+}